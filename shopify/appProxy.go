@@ -0,0 +1,35 @@
+package shopify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+//VerifyAppProxySignature checks the signature shopify appends to the requests it forwards to an app proxy.
+//Unlike the oauth hmac, the message is made of the sorted key=value pairs with no separator between them,
+//the values of a repeated key being joined by commas.
+func VerifyAppProxySignature(secret string, query url.Values) bool {
+	signature := query.Get("signature")
+	if signature == "" {
+		return false
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	var pairs []string
+	for key, values := range query {
+		if key == "signature" {
+			continue
+		}
+		pairs = append(pairs, key+"="+strings.Join(values, ","))
+	}
+	sort.Strings(pairs)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join(pairs, "")))
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,33 @@
+package shopify
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// signedProxyQuery is an app proxy request signed with "hush", with a repeated key
+const signedProxyQuery = "extra=1&extra=2&shop=shop-name.myshopify.com&logged_in_customer_id=1&path_prefix=%2Fapps%2Fawesome_reviews&timestamp=1317327555&signature=4c68c8624d737112c91818c11017d24d334b524cb5c2b8ba08daa056f7395ddb"
+
+// Should verify a query signed with the app secret
+func TestVerifyAppProxySignature(t *testing.T) {
+	query, _ := url.ParseQuery(signedProxyQuery)
+
+	assert.T(t, VerifyAppProxySignature("hush", query))
+	assert.T(t, !VerifyAppProxySignature("not-the-secret", query))
+}
+
+// Should not verify a tampered or unsigned query
+func TestVerifyAppProxySignatureTampered(t *testing.T) {
+	query, _ := url.ParseQuery(signedProxyQuery)
+	query.Set("logged_in_customer_id", "2")
+	assert.T(t, !VerifyAppProxySignature("hush", query))
+
+	query, _ = url.ParseQuery(signedProxyQuery)
+	query.Del("signature")
+	assert.T(t, !VerifyAppProxySignature("hush", query))
+
+	query.Set("signature", "not-hex")
+	assert.T(t, !VerifyAppProxySignature("hush", query))
+}
@@ -0,0 +1,137 @@
+package shopify
+
+import (
+	"fmt"
+	"math/big"
+)
+
+//CreateApplicationCharge creates a one-time charge for the app, the merchant approves it at the returned
+//charge's ConfirmationURL and is then sent back to its ReturnURL. Set Test for charges made while developing.
+func (shop *Shopify) CreateApplicationCharge(charge ApplicationCharge) (*ApplicationCharge, []error) {
+	body := map[string]interface{}{
+		"name":       charge.Name,
+		"price":      charge.Price.Amount,
+		"return_url": charge.ReturnURL,
+	}
+	if charge.Test != nil && *charge.Test {
+		body["test"] = true
+	}
+	var chargeResponse ApplicationChargeResponse
+	response, errors := shop.Post("application_charges", map[string]interface{}{"application_charge": body})
+	if err := unmarshal(response, errors, &chargeResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &chargeResponse.ApplicationCharge, nil
+}
+
+//GetApplicationCharge returns a one-time application charge given its id
+func (shop *Shopify) GetApplicationCharge(chargeID int64) (*ApplicationCharge, []error) {
+	var chargeResponse ApplicationChargeResponse
+	response, errors := shop.Get(fmt.Sprintf("application_charges/%v", chargeID))
+	if err := unmarshal(response, errors, &chargeResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &chargeResponse.ApplicationCharge, nil
+}
+
+//ActivateApplicationCharge activates a one-time charge the merchant accepted, so that it gets billed
+func (shop *Shopify) ActivateApplicationCharge(chargeID int64) (*ApplicationCharge, []error) {
+	var chargeResponse ApplicationChargeResponse
+	response, errors := shop.Post(fmt.Sprintf("application_charges/%v/activate", chargeID), map[string]interface{}{
+		"application_charge": map[string]interface{}{"id": chargeID},
+	})
+	if err := unmarshal(response, errors, &chargeResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &chargeResponse.ApplicationCharge, nil
+}
+
+//CreateRecurringCharge creates a subscription to the app, approved by the merchant at its ConfirmationURL.
+//The trial days, and the capped amount with its terms for usage billing, are only sent when set.
+func (shop *Shopify) CreateRecurringCharge(charge RecurringApplicationCharge) (*RecurringApplicationCharge, []error) {
+	body := map[string]interface{}{
+		"name":       charge.Name,
+		"price":      charge.Price.Amount,
+		"return_url": charge.ReturnURL,
+	}
+	if charge.TrialDays > 0 {
+		body["trial_days"] = charge.TrialDays
+	}
+	if charge.CappedAmount.Amount != "" {
+		if charge.Terms == "" {
+			return nil, []error{fmt.Errorf("a capped amount needs terms describing the usage charges")}
+		}
+		body["capped_amount"] = charge.CappedAmount.Amount
+		body["terms"] = charge.Terms
+	}
+	if charge.Test != nil && *charge.Test {
+		body["test"] = true
+	}
+	var chargeResponse RecurringApplicationChargeResponse
+	response, errors := shop.Post("recurring_application_charges", map[string]interface{}{"recurring_application_charge": body})
+	if err := unmarshal(response, errors, &chargeResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &chargeResponse.RecurringApplicationCharge, nil
+}
+
+//GetRecurringCharge returns a recurring application charge given its id
+func (shop *Shopify) GetRecurringCharge(chargeID int64) (*RecurringApplicationCharge, []error) {
+	var chargeResponse RecurringApplicationChargeResponse
+	response, errors := shop.Get(fmt.Sprintf("recurring_application_charges/%v", chargeID))
+	if err := unmarshal(response, errors, &chargeResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &chargeResponse.RecurringApplicationCharge, nil
+}
+
+//ActivateRecurringCharge activates a subscription the merchant accepted, starting its billing cycle
+func (shop *Shopify) ActivateRecurringCharge(chargeID int64) (*RecurringApplicationCharge, []error) {
+	var chargeResponse RecurringApplicationChargeResponse
+	response, errors := shop.Post(fmt.Sprintf("recurring_application_charges/%v/activate", chargeID), map[string]interface{}{
+		"recurring_application_charge": map[string]interface{}{"id": chargeID},
+	})
+	if err := unmarshal(response, errors, &chargeResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &chargeResponse.RecurringApplicationCharge, nil
+}
+
+//CancelRecurringCharge cancels a subscription
+func (shop *Shopify) CancelRecurringCharge(chargeID int64) []error {
+	_, errors := shop.Delete(fmt.Sprintf("recurring_application_charges/%v", chargeID))
+	return errors
+}
+
+//CreateUsageCharge bills a usage charge against a capped subscription. A price above the balance
+//remaining under the cap is refused before calling shopify, which would reject it anyway.
+func (shop *Shopify) CreateUsageCharge(recurringChargeID int64, description string, price string) (*UsageCharge, []error) {
+	amount, ok := new(big.Rat).SetString(price)
+	if !ok || amount.Sign() <= 0 {
+		return nil, []error{fmt.Errorf("invalid usage charge price %q", price)}
+	}
+	charge, errs := shop.GetRecurringCharge(recurringChargeID)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if charge.CappedAmount.Amount == "" {
+		return nil, []error{fmt.Errorf("recurring charge %v has no capped amount", recurringChargeID)}
+	}
+	remaining, ok := new(big.Rat).SetString(charge.BalanceRemaining.Amount)
+	if !ok {
+		return nil, []error{fmt.Errorf("invalid balance remaining %q", charge.BalanceRemaining.Amount)}
+	}
+	if amount.Cmp(remaining) > 0 {
+		return nil, []error{fmt.Errorf("usage charge of %v exceeds the %v remaining under the capped amount of %v",
+			price, charge.BalanceRemaining.Amount, charge.CappedAmount.Amount)}
+	}
+
+	var usageResponse UsageChargeResponse
+	response, errors := shop.Post(fmt.Sprintf("recurring_application_charges/%v/usage_charges", recurringChargeID), map[string]interface{}{
+		"usage_charge": map[string]interface{}{"description": description, "price": price},
+	})
+	if err := unmarshal(response, errors, &usageResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &usageResponse.UsageCharge, nil
+}
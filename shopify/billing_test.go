@@ -0,0 +1,158 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should POST the test charge and decode its confirmation URL
+func TestCreateApplicationCharge(t *testing.T) {
+	fixture := loadFixture(t, "application_charge.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/admin/application_charges.json", r.URL.Path)
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, map[string]interface{}{
+			"name":       "Super Duper Expensive action",
+			"price":      "100.00",
+			"return_url": "http://super-duper.shopifyapps.com/",
+			"test":       true,
+		}, body["application_charge"])
+		w.WriteHeader(201)
+		w.Write(fixture)
+	})
+	defer server.Close()
+
+	test := true
+	charge, errs := mock.CreateApplicationCharge(ApplicationCharge{
+		Name:      "Super Duper Expensive action",
+		Price:     Money{Amount: "100.00"},
+		ReturnURL: "http://super-duper.shopifyapps.com/",
+		Test:      &test,
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "https://jsmith.myshopify.com/admin/charges/755357713/1017262346/ApplicationCharge/confirm_application_charge?signature=BAh7BzoHaWRpBAoOoTw%3D", charge.ConfirmationURL)
+	assert.Equal(t, "pending", charge.Status)
+	assert.T(t, *charge.Test)
+}
+
+// Should POST the activation of the accepted charge
+func TestActivateApplicationCharge(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/admin/application_charges/1017262346/activate.json", r.URL.Path)
+		w.Write([]byte(`{"application_charge": {"id": 1017262346, "status": "active", "price": "100.00", "test": null}}`))
+	})
+	defer server.Close()
+
+	charge, errs := mock.ActivateApplicationCharge(1017262346)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "active", charge.Status)
+	assert.T(t, charge.Test == nil)
+}
+
+// Should create a capped subscription, then activate it once accepted
+func TestRecurringChargeLifecycle(t *testing.T) {
+	fixture := loadFixture(t, "recurring_application_charge.json")
+	var requests []string
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/admin/recurring_application_charges.json":
+			assert.Equal(t, map[string]interface{}{
+				"name":          "Super Duper Plan",
+				"price":         "10.00",
+				"return_url":    "http://super-duper.shopifyapps.com",
+				"trial_days":    float64(7),
+				"capped_amount": "100.00",
+				"terms":         "$1 for 1000 emails",
+			}, body["recurring_application_charge"])
+			w.WriteHeader(201)
+			w.Write(fixture)
+		case "/admin/recurring_application_charges/455696195/activate.json":
+			w.Write([]byte(`{"recurring_application_charge": {"id": 455696195, "status": "active", "billing_on": "2023-10-10"}}`))
+		default:
+			t.Errorf("unexpected request to %v", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	charge, errs := mock.CreateRecurringCharge(RecurringApplicationCharge{
+		Name:         "Super Duper Plan",
+		Price:        Money{Amount: "10.00"},
+		ReturnURL:    "http://super-duper.shopifyapps.com",
+		TrialDays:    7,
+		CappedAmount: Money{Amount: "100.00"},
+		Terms:        "$1 for 1000 emails",
+	})
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "pending", charge.Status)
+	assert.Equal(t, "100.00", charge.CappedAmount.Amount)
+	assert.T(t, charge.ConfirmationURL != "")
+
+	charge, errs = mock.ActivateRecurringCharge(charge.ID)
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "active", charge.Status)
+	assert.Equal(t, "2023-10-10", charge.BillingOn)
+	assert.Equal(t, []string{
+		"POST /admin/recurring_application_charges.json",
+		"POST /admin/recurring_application_charges/455696195/activate.json",
+	}, requests)
+}
+
+// Should refuse a capped amount without terms
+func TestCreateRecurringChargeCappedWithoutTerms(t *testing.T) {
+	_, errs := shop.CreateRecurringCharge(RecurringApplicationCharge{Name: "Plan", Price: Money{Amount: "10.00"}, CappedAmount: Money{Amount: "100.00"}})
+
+	assert.Equal(t, 1, len(errs))
+}
+
+// Should POST the usage charge when it fits under the cap
+func TestCreateUsageCharge(t *testing.T) {
+	recurring := loadFixture(t, "recurring_application_charge.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			assert.Equal(t, "/admin/recurring_application_charges/455696195.json", r.URL.Path)
+			w.Write(recurring)
+			return
+		}
+		assert.Equal(t, "/admin/recurring_application_charges/455696195/usage_charges.json", r.URL.Path)
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, map[string]interface{}{"description": "Super Mega Plan 1000 emails", "price": "1.00"}, body["usage_charge"])
+		w.WriteHeader(201)
+		w.Write([]byte(`{"usage_charge": {"id": 1034618207, "description": "Super Mega Plan 1000 emails", "price": "1.00",
+			"recurring_application_charge_id": 455696195, "balance_used": 1.0, "balance_remaining": 99.0}}`))
+	})
+	defer server.Close()
+
+	usage, errs := mock.CreateUsageCharge(455696195, "Super Mega Plan 1000 emails", "1.00")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, int64(1034618207), usage.ID)
+	assert.Equal(t, "1.00", usage.Price.Amount)
+}
+
+// Should refuse a usage charge above the balance remaining without POSTing it
+func TestCreateUsageChargeExceedsCap(t *testing.T) {
+	recurring := loadFixture(t, "recurring_application_charge.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("unexpected %v to %v", r.Method, r.URL.Path)
+		}
+		w.Write(recurring)
+	})
+	defer server.Close()
+
+	_, errs := mock.CreateUsageCharge(455696195, "Super Mega Plan 200000 emails", "100.01")
+
+	assert.Equal(t, 1, len(errs))
+}
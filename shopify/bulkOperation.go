@@ -0,0 +1,306 @@
+package shopify
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// bulkOperationPollInterval is how long runBulkQuery waits between two checks of the operation
+const bulkOperationPollInterval = 2 * time.Second
+
+// bulkResultMaxLine is the longest line of a bulk operation result, a product with a long description included
+const bulkResultMaxLine = 4 * 1024 * 1024
+
+const bulkOperationRunQueryMutation = `mutation($query: String!) {
+  bulkOperationRunQuery(query: $query) {
+    bulkOperation { id status }
+    userErrors { field message }
+  }
+}`
+
+const bulkProductsQuery = `{
+  products {
+    edges {
+      node {
+        legacyResourceId title handle descriptionHtml vendor productType status tags templateSuffix
+        createdAt updatedAt publishedAt
+        variants {
+          edges {
+            node {
+              id legacyResourceId title sku barcode price compareAtPrice position
+              inventoryQuantity inventoryPolicy taxable createdAt updatedAt
+              selectedOptions { value }
+              inventoryItem { legacyResourceId }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+const currentBulkOperationQuery = `{
+  currentBulkOperation { id status errorCode objectCount fileSize url partialDataUrl query createdAt completedAt }
+}`
+
+const bulkOperationCancelMutation = `mutation($id: ID!) {
+  bulkOperationCancel(id: $id) {
+    bulkOperation { id status }
+    userErrors { field message }
+  }
+}`
+
+// finishedBulkOperationStatuses are the statuses of bulk operations that can no longer be canceled
+var finishedBulkOperationStatuses = map[string]bool{
+	"COMPLETED": true,
+	"CANCELED":  true,
+	"FAILED":    true,
+	"EXPIRED":   true,
+}
+
+//GetCurrentBulkOperation returns the last bulk operation started by the app, nil when there is none.
+//Poll it until its status is COMPLETED to get the URL of the results.
+func (shop *Shopify) GetCurrentBulkOperation() (*BulkOperation, []error) {
+	var data struct {
+		CurrentBulkOperation *BulkOperation `json:"currentBulkOperation"`
+	}
+	if errs := shop.graphQL(currentBulkOperationQuery, nil, &data); len(errs) > 0 {
+		return nil, errs
+	}
+	return data.CurrentBulkOperation, nil
+}
+
+//CancelBulkOperation asks shopify to cancel a running bulk operation, so that another one can be started.
+//Canceling an operation that already finished does nothing.
+func (shop *Shopify) CancelBulkOperation(id string) []error {
+	var data struct {
+		BulkOperationCancel struct {
+			BulkOperation *BulkOperation     `json:"bulkOperation"`
+			UserErrors    []graphQLUserError `json:"userErrors"`
+		} `json:"bulkOperationCancel"`
+	}
+	errs := shop.graphQL(bulkOperationCancelMutation, map[string]interface{}{"id": id}, &data)
+	if len(errs) == 0 {
+		return nil
+	}
+	result := data.BulkOperationCancel
+	if len(result.UserErrors) > 0 && result.BulkOperation != nil && finishedBulkOperationStatuses[result.BulkOperation.Status] {
+		return nil
+	}
+	return errs
+}
+
+//ExportAllProducts calls fn for every product of the store along with its variants. Instead of paging
+//through the REST api it runs a GraphQL bulk operation, polls it until it completes and streams its
+//results, which scales to catalogs of 100k+ products. It stops on the first error returned by fn and
+//cancels the operation when the context is done.
+func (shop *Shopify) ExportAllProducts(fn func(Product) error) []error {
+	operation, errs := shop.runBulkQuery(bulkProductsQuery)
+	if len(errs) > 0 {
+		return errs
+	}
+	var product *Product
+	errs = shop.streamBulkResults(operation.URL, func(line []byte) error {
+		var object struct {
+			ParentID string `json:"__parentId"`
+		}
+		if err := json.Unmarshal(line, &object); err != nil {
+			return err
+		}
+		if object.ParentID == "" {
+			if product != nil {
+				if err := fn(*product); err != nil {
+					return err
+				}
+			}
+			decoded, err := decodeBulkProduct(line)
+			if err != nil {
+				return err
+			}
+			product = &decoded
+			return nil
+		}
+		// the variants of a product follow it in the results
+		variant, err := decodeBulkVariant(line)
+		if err != nil {
+			return err
+		}
+		if product != nil {
+			variant.ProductID = product.ID
+			product.Variants = append(product.Variants, variant)
+		}
+		return nil
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+	if product != nil {
+		if err := fn(*product); err != nil {
+			return []error{err}
+		}
+	}
+	return nil
+}
+
+// runBulkQuery starts a bulk operation running the query and polls it until it completes
+func (shop *Shopify) runBulkQuery(query string) (*BulkOperation, []error) {
+	var data struct {
+		BulkOperationRunQuery struct {
+			BulkOperation *BulkOperation `json:"bulkOperation"`
+		} `json:"bulkOperationRunQuery"`
+	}
+	if errs := shop.graphQL(bulkOperationRunQueryMutation, map[string]interface{}{"query": query}, &data); len(errs) > 0 {
+		return nil, errs
+	}
+	started := data.BulkOperationRunQuery.BulkOperation
+	if started == nil {
+		return nil, []error{fmt.Errorf("no bulk operation started")}
+	}
+	ctx := shop.context()
+	for {
+		if err := ctx.Err(); err != nil {
+			shop.CancelBulkOperation(started.ID)
+			return nil, []error{err}
+		}
+		operation, errs := shop.GetCurrentBulkOperation()
+		if len(errs) > 0 {
+			return nil, errs
+		}
+		if operation == nil || operation.ID != started.ID {
+			return nil, []error{fmt.Errorf("bulk operation %v is no longer the current one", started.ID)}
+		}
+		if operation.Status == "COMPLETED" {
+			return operation, nil
+		}
+		if finishedBulkOperationStatuses[operation.Status] {
+			return nil, []error{fmt.Errorf("bulk operation %v %s %s", operation.ID, strings.ToLower(operation.Status), operation.ErrorCode)}
+		}
+		shop.clock.Sleep(bulkOperationPollInterval)
+	}
+}
+
+// streamBulkResults downloads the JSONL results of a bulk operation and calls fn for each line,
+// an operation that found nothing has no results to download
+func (shop *Shopify) streamBulkResults(url string, fn func(line []byte) error) []error {
+	if url == "" {
+		return nil
+	}
+	request, err := http.NewRequestWithContext(shop.context(), "GET", url, nil)
+	if err != nil {
+		return []error{err}
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return []error{err}
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return []error{fmt.Errorf("downloading the bulk operation results: %s", response.Status)}
+	}
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 64*1024), bulkResultMaxLine)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return []error{err}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// decodeBulkProduct decodes a product line of the bulk results in its REST form
+func decodeBulkProduct(line []byte) (Product, error) {
+	var node struct {
+		LegacyResourceID int64     `json:"legacyResourceId,string"`
+		Title            string    `json:"title"`
+		Handle           string    `json:"handle"`
+		DescriptionHTML  string    `json:"descriptionHtml"`
+		Vendor           string    `json:"vendor"`
+		ProductType      string    `json:"productType"`
+		Status           string    `json:"status"`
+		Tags             []string  `json:"tags"`
+		TemplateSuffix   *string   `json:"templateSuffix"`
+		CreatedAt        ShopTime  `json:"createdAt"`
+		UpdatedAt        ShopTime  `json:"updatedAt"`
+		PublishedAt      *ShopTime `json:"publishedAt"`
+	}
+	if err := json.Unmarshal(line, &node); err != nil {
+		return Product{}, err
+	}
+	product := Product{
+		ID:          node.LegacyResourceID,
+		Title:       node.Title,
+		Handle:      node.Handle,
+		BodyHTML:    node.DescriptionHTML,
+		Vendor:      node.Vendor,
+		ProductType: node.ProductType,
+		Status:      strings.ToLower(node.Status),
+		Tags:        joinTags(node.Tags),
+		CreatedAt:   node.CreatedAt,
+		UpdatedAt:   node.UpdatedAt,
+		PublishedAt: node.PublishedAt,
+	}
+	if node.TemplateSuffix != nil {
+		product.TemplateSuffix = *node.TemplateSuffix
+	}
+	return product, nil
+}
+
+// decodeBulkVariant decodes a variant line of the bulk results in its REST form
+func decodeBulkVariant(line []byte) (Variant, error) {
+	var node struct {
+		LegacyResourceID  int64    `json:"legacyResourceId,string"`
+		Title             string   `json:"title"`
+		SKU               string   `json:"sku"`
+		Barcode           string   `json:"barcode"`
+		Price             Money    `json:"price"`
+		CompareAtPrice    Money    `json:"compareAtPrice"`
+		Position          int      `json:"position"`
+		InventoryQuantity int      `json:"inventoryQuantity"`
+		InventoryPolicy   string   `json:"inventoryPolicy"`
+		Taxable           bool     `json:"taxable"`
+		CreatedAt         ShopTime `json:"createdAt"`
+		UpdatedAt         ShopTime `json:"updatedAt"`
+		SelectedOptions   []struct {
+			Value string `json:"value"`
+		} `json:"selectedOptions"`
+		InventoryItem struct {
+			LegacyResourceID int64 `json:"legacyResourceId,string"`
+		} `json:"inventoryItem"`
+	}
+	if err := json.Unmarshal(line, &node); err != nil {
+		return Variant{}, err
+	}
+	variant := Variant{
+		ID:                node.LegacyResourceID,
+		Title:             node.Title,
+		SKU:               node.SKU,
+		BarCode:           node.Barcode,
+		Price:             node.Price,
+		CompareAtPrice:    node.CompareAtPrice,
+		Position:          node.Position,
+		InventoryItemID:   node.InventoryItem.LegacyResourceID,
+		InventoryQuantity: node.InventoryQuantity,
+		InventoryPolicy:   strings.ToLower(node.InventoryPolicy),
+		Taxable:           node.Taxable,
+		CreatedAt:         node.CreatedAt,
+		UpdatedAt:         node.UpdatedAt,
+	}
+	options := []*string{&variant.Option1, &variant.Option2, &variant.Option3}
+	for i, option := range node.SelectedOptions {
+		if i < len(options) {
+			*options[i] = option.Value
+		}
+	}
+	return variant, nil
+}
@@ -0,0 +1,197 @@
+package shopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should decode the running bulk operation
+func TestGetCurrentBulkOperation(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/api/graphql.json", "graphql_bulk_operation_running.json"))
+	defer server.Close()
+
+	operation, errs := mock.GetCurrentBulkOperation()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "gid://shopify/BulkOperation/720918", operation.ID)
+	assert.Equal(t, "RUNNING", operation.Status)
+	assert.Equal(t, "", operation.ErrorCode)
+	assert.Equal(t, int64(42), operation.ObjectCount)
+	assert.Equal(t, "", operation.URL)
+	assert.T(t, operation.CompletedAt == nil)
+}
+
+// Should return nil when no bulk operation was ever started
+func TestGetCurrentBulkOperationNone(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"currentBulkOperation": null}}`))
+	})
+	defer server.Close()
+
+	operation, errs := mock.GetCurrentBulkOperation()
+
+	assert.T(t, errs == nil, errs)
+	assert.T(t, operation == nil)
+}
+
+// Should send the cancel mutation for the operation
+func TestCancelBulkOperation(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, map[string]interface{}{"id": "gid://shopify/BulkOperation/720918"}, body["variables"])
+		w.Write([]byte(`{"data": {"bulkOperationCancel": {
+			"bulkOperation": {"id": "gid://shopify/BulkOperation/720918", "status": "CANCELING"},
+			"userErrors": []
+		}}}`))
+	})
+	defer server.Close()
+
+	errs := mock.CancelBulkOperation("gid://shopify/BulkOperation/720918")
+
+	assert.T(t, errs == nil, errs)
+}
+
+// Should ignore the user error of an operation that already completed and report the others
+func TestCancelBulkOperationFinished(t *testing.T) {
+	status := "COMPLETED"
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"bulkOperationCancel": {
+			"bulkOperation": {"id": "gid://shopify/BulkOperation/720918", "status": "` + status + `"},
+			"userErrors": [{"field": null, "message": "A bulk operation cannot be canceled when it is ` + status + `"}]
+		}}}`))
+	})
+	defer server.Close()
+
+	assert.T(t, mock.CancelBulkOperation("gid://shopify/BulkOperation/720918") == nil)
+
+	status = "RUNNING"
+	errs := mock.CancelBulkOperation("gid://shopify/BulkOperation/720918")
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, ErrorMessages{"base": {"A bulk operation cannot be canceled when it is RUNNING"}}, findShopifyError(errs).Errors)
+}
+
+// bulkExportHandler answers the mutation starting the export, reports the operation running once and then
+// completed with its results at /bulk/products.jsonl, and counts the cancel mutations it receives
+func bulkExportHandler(t *testing.T, onPoll func(), cancels *int) http.HandlerFunc {
+	results := loadFixture(t, "bulk_products.jsonl")
+	polls := 0
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/bulk/products.jsonl" {
+			w.Write(results)
+			return
+		}
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case strings.Contains(body.Query, "bulkOperationRunQuery"):
+			w.Write([]byte(`{"data": {"bulkOperationRunQuery": {
+				"bulkOperation": {"id": "gid://shopify/BulkOperation/720918", "status": "CREATED"},
+				"userErrors": []
+			}}}`))
+		case strings.Contains(body.Query, "bulkOperationCancel"):
+			*cancels++
+			w.Write([]byte(`{"data": {"bulkOperationCancel": {
+				"bulkOperation": {"id": "gid://shopify/BulkOperation/720918", "status": "CANCELING"},
+				"userErrors": []
+			}}}`))
+		default:
+			polls++
+			if onPoll != nil {
+				onPoll()
+			}
+			status, url := "RUNNING", ""
+			if polls > 1 {
+				status, url = "COMPLETED", "http://"+r.Host+"/bulk/products.jsonl"
+			}
+			w.Write([]byte(`{"data": {"currentBulkOperation": {
+				"id": "gid://shopify/BulkOperation/720918", "status": "` + status + `", "url": "` + url + `"
+			}}}`))
+		}
+	}
+}
+
+// Should poll the bulk operation until it completes and rebuild each product with its variants
+func TestExportAllProducts(t *testing.T) {
+	clock := newFakeClock()
+	cancels := 0
+	mock, server := newMockShopify(t, bulkExportHandler(t, nil, &cancels), withClock(clock))
+	defer server.Close()
+
+	var products []Product
+	errs := mock.ExportAllProducts(func(product Product) error {
+		products = append(products, product)
+		return nil
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []time.Duration{bulkOperationPollInterval}, clock.Sleeps())
+	assert.Equal(t, 2, len(products))
+
+	nano := products[0]
+	assert.Equal(t, int64(632910392), nano.ID)
+	assert.Equal(t, "IPod Nano - 8GB", nano.Title)
+	assert.Equal(t, "active", nano.Status)
+	assert.Equal(t, "Emotive, Flash Memory", nano.Tags)
+	assert.Equal(t, 2, len(nano.Variants))
+	assert.Equal(t, int64(808950810), nano.Variants[0].ID)
+	assert.Equal(t, int64(632910392), nano.Variants[0].ProductID)
+	assert.Equal(t, "Pink", nano.Variants[0].Option1)
+	assert.Equal(t, "continue", nano.Variants[0].InventoryPolicy)
+	assert.Equal(t, "199.00", nano.Variants[0].Price.Amount)
+	assert.Equal(t, "249.00", nano.Variants[1].CompareAtPrice.Amount)
+
+	touch := products[1]
+	assert.Equal(t, int64(921728736), touch.ID)
+	assert.Equal(t, "draft", touch.Status)
+	assert.Equal(t, "special", touch.TemplateSuffix)
+	assert.T(t, touch.PublishedAt == nil)
+	assert.Equal(t, 1, len(touch.Variants))
+	assert.Equal(t, int64(447654529), touch.Variants[0].InventoryItemID)
+	assert.Equal(t, 0, cancels)
+}
+
+// Should stop with the error returned by fn
+func TestExportAllProductsStop(t *testing.T) {
+	cancels := 0
+	mock, server := newMockShopify(t, bulkExportHandler(t, nil, &cancels), withClock(newFakeClock()))
+	defer server.Close()
+
+	stop := errors.New("stop")
+	calls := 0
+	errs := mock.ExportAllProducts(func(product Product) error {
+		calls++
+		return stop
+	})
+
+	assert.Equal(t, []error{stop}, errs)
+	assert.Equal(t, 1, calls)
+}
+
+// Should cancel the bulk operation once the context is done
+func TestExportAllProductsCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancels := 0
+	mock, server := newMockShopify(t, bulkExportHandler(t, cancel, &cancels), withClock(newFakeClock()))
+	defer server.Close()
+
+	calls := 0
+	errs := mock.WithContext(ctx).ExportAllProducts(func(product Product) error {
+		calls++
+		return nil
+	})
+
+	assert.Equal(t, []error{context.Canceled}, errs)
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, 1, cancels)
+}
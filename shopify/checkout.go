@@ -0,0 +1,88 @@
+package shopify
+
+import (
+	"fmt"
+	"time"
+)
+
+// shippingRatesAttempts is how many times GetShippingRates asks for the rates of a checkout before
+// concluding none apply, shippingRatesPollInterval the wait between them
+const (
+	shippingRatesAttempts     = 5
+	shippingRatesPollInterval = time.Second
+)
+
+//CreateCheckout creates a checkout for the given variants and returns it with its token and web URL
+func (shop *Shopify) CreateCheckout(lineItems []CheckoutLineItem, email string) (*Checkout, []error) {
+	body := map[string]interface{}{}
+	if email != "" {
+		body["email"] = email
+	}
+	return shop.createCheckout(lineItems, body)
+}
+
+// createCheckout creates a checkout for the given variants along with the other checkout fields of body
+func (shop *Shopify) createCheckout(lineItems []CheckoutLineItem, body map[string]interface{}) (*Checkout, []error) {
+	if len(lineItems) == 0 {
+		return nil, []error{fmt.Errorf("a checkout needs at least one line item")}
+	}
+	items := make([]map[string]interface{}, 0, len(lineItems))
+	for i, item := range lineItems {
+		if item.VariantID == 0 || item.Quantity < 1 {
+			return nil, []error{fmt.Errorf("line item %d needs a variant id and a positive quantity", i)}
+		}
+		items = append(items, map[string]interface{}{"variant_id": item.VariantID, "quantity": item.Quantity})
+	}
+	body["line_items"] = items
+	var checkoutResponse CheckoutResponse
+	response, errors := shop.Post("checkouts", map[string]interface{}{"checkout": body})
+	if err := unmarshal(response, errors, &checkoutResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &checkoutResponse.Checkout, nil
+}
+
+//GetShippingRates returns the shipping rates the store offers for the given variants shipped to destination.
+//It creates a checkout for them and polls its rates, which shopify computes in the background.
+func (shop *Shopify) GetShippingRates(destination Address, items []CheckoutLineItem) ([]ShippingRate, []error) {
+	checkout, errs := shop.createCheckout(items, map[string]interface{}{"shipping_address": destination})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	endpoint := fmt.Sprintf("checkouts/%v/shipping_rates", checkout.Token)
+	for attempt := 1; ; attempt++ {
+		var ratesResponse ShippingRatesResponse
+		response, errors := shop.Get(endpoint)
+		if err := unmarshal(response, errors, &ratesResponse); len(err) > 0 {
+			return nil, err
+		}
+		// an empty list means the rates are still being computed
+		if len(ratesResponse.ShippingRates) > 0 || attempt == shippingRatesAttempts {
+			return ratesResponse.ShippingRates, nil
+		}
+		shop.clock.Sleep(shippingRatesPollInterval)
+	}
+}
+
+//GetRecoveryURLs returns the recovery URL of every abandoned checkout updated since the given time,
+//keyed by checkout id. Checkouts completed in the meantime are left out.
+func (shop *Shopify) GetRecoveryURLs(since time.Time) (map[int64]string, []error) {
+	urls := make(map[int64]string)
+	parameters := map[string]string{"updated_at_min": since.Format(time.RFC3339), "limit": "250"}
+	errs := shop.paginate("checkouts", parameters, func(page []byte) []error {
+		var checkouts AbandonedCheckoutsResponse
+		if err := unmarshal(page, nil, &checkouts); len(err) > 0 {
+			return err
+		}
+		for _, checkout := range checkouts.Checkouts {
+			if checkout.CompletedAt == nil && checkout.AbandonedCheckoutURL != "" {
+				urls[checkout.ID] = checkout.AbandonedCheckoutURL
+			}
+		}
+		return nil
+	})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return urls, nil
+}
@@ -0,0 +1,118 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should page the abandoned checkouts and map the recovery URLs of the incomplete ones
+func TestGetRecoveryURLs(t *testing.T) {
+	first := loadFixture(t, "checkouts.json")
+	second := loadFixture(t, "checkouts_page_2.json")
+	pages := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		assert.Equal(t, "/admin/checkouts.json", r.URL.Path)
+		if r.URL.Query().Get("page_info") == "" {
+			assert.Equal(t, "2012-10-01T00:00:00Z", r.URL.Query().Get("updated_at_min"))
+			w.Header().Set("Link", `<https://mock.myshopify.com/admin/checkouts.json?page_info=bmV4dA&limit=250>; rel="next"`)
+			w.Write(first)
+			return
+		}
+		assert.Equal(t, "bmV4dA", r.URL.Query().Get("page_info"))
+		w.Write(second)
+	})
+	defer server.Close()
+
+	urls, errs := mock.GetRecoveryURLs(time.Date(2012, 10, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, pages)
+	assert.Equal(t, map[int64]string{
+		450789469: "https://checkout.local/690933842/checkouts/2a1ace52255252df566eb5c0e8a6a7e4/recover?key=a18899e8",
+		450789471: "https://checkout.local/690933842/checkouts/3f4a1e23c2b1d0e9f8a7b6c5d4e3f2a1/recover?key=5b7d0f2e",
+	}, urls)
+}
+
+// Should POST the variants and decode the created checkout's token and web URL
+func TestCreateCheckout(t *testing.T) {
+	fixture := loadFixture(t, "checkout.json")
+	var body map[string]map[string]interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/admin/checkouts.json", r.URL.Path)
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write(fixture)
+	})
+	defer server.Close()
+
+	checkout, errs := mock.CreateCheckout([]CheckoutLineItem{{VariantID: 39072856, Quantity: 2}}, "me@example.com")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "me@example.com", body["checkout"]["email"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"variant_id": float64(39072856), "quantity": float64(2)}}, body["checkout"]["line_items"])
+	assert.Equal(t, "b490a9220cd14d7344024f4874f640a6", checkout.Token)
+	assert.Equal(t, "https://apple.myshopify.com/690933842/checkouts/b490a9220cd14d7344024f4874f640a6", checkout.WebURL)
+	assert.Equal(t, "199.00", checkout.LineItems[0].Price.Amount)
+}
+
+// Should reject line items without a variant or quantity
+func TestCreateCheckoutInvalidLineItems(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %v", r.URL.Path)
+	})
+	defer server.Close()
+
+	for _, items := range [][]CheckoutLineItem{nil, {{Quantity: 1}}, {{VariantID: 39072856}}} {
+		_, errs := mock.CreateCheckout(items, "")
+		assert.Equal(t, 1, len(errs))
+	}
+}
+
+// Should create a checkout shipping to the destination and poll its rates until shopify computed them
+func TestGetShippingRates(t *testing.T) {
+	checkout := loadFixture(t, "checkout.json")
+	rates := loadFixture(t, "shipping_rates.json")
+	var body map[string]map[string]interface{}
+	polls := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/checkouts.json":
+			json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusAccepted)
+			w.Write(checkout)
+		case "/admin/checkouts/b490a9220cd14d7344024f4874f640a6/shipping_rates.json":
+			polls++
+			if polls == 1 {
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte(`{"shipping_rates": []}`))
+				return
+			}
+			w.Write(rates)
+		default:
+			t.Errorf("unexpected request to %v", r.URL.Path)
+		}
+	}, withClock(newFakeClock()))
+	defer server.Close()
+
+	destination := Address{Address1: "126 York St", City: "Ottawa", ProvinceCode: "ON", CountryCode: "CA", Zip: "K1N 5T5"}
+	shippingRates, errs := mock.GetShippingRates(destination, []CheckoutLineItem{{VariantID: 39072856, Quantity: 2}})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, polls)
+	assert.Equal(t, map[string]interface{}{
+		"address1": "126 York St", "city": "Ottawa", "province_code": "ON", "country_code": "CA", "zip": "K1N 5T5",
+	}, body["checkout"]["shipping_address"])
+	assert.Equal(t, 2, len(shippingRates))
+	assert.Equal(t, "Standard Shipping", shippingRates[0].Title)
+	assert.Equal(t, "10.00", shippingRates[0].Price.Amount)
+	assert.Equal(t, "Expedited Parcel", shippingRates[1].Title)
+	assert.Equal(t, "14.42", shippingRates[1].Price.Amount)
+	assert.T(t, shippingRates[1].PhoneRequired)
+	assert.Equal(t, 2, len(shippingRates[1].DeliveryRange))
+}
@@ -0,0 +1,28 @@
+package shopify
+
+import "time"
+
+// clock abstracts time so the limiter and the retries can be driven instantly in tests
+type clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// withClock Replaces the clock used by the limiter and the retries
+func withClock(c clock) Option {
+	return func(shopify *Shopify) {
+		shopify.clock = c
+		if shopify.limiter != nil {
+			shopify.limiter.clock = c
+			shopify.limiter.last = c.Now()
+		}
+	}
+}
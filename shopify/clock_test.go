@@ -0,0 +1,108 @@
+package shopify
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+// fakeClock only moves when slept on, recording every sleep
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.Sleep(d)
+	after := make(chan time.Time, 1)
+	after <- c.Now()
+	return after
+}
+
+func (c *fakeClock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
+
+// Should back off exponentially between throttled attempts
+func TestRetryBackoffTiming(t *testing.T) {
+	clock := newFakeClock()
+	calls := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, withClock(clock))
+	defer server.Close()
+
+	_, errs := mock.GetOrdersCount()
+
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, 429, findShopifyError(errs).StatusCode)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second}, clock.Sleeps())
+}
+
+// Should keep every jittered sleep below its backoff
+func TestRetryJitterTiming(t *testing.T) {
+	clock := newFakeClock()
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, withClock(clock), WithRetryJitter(true), WithMaxRetries(6))
+	defer server.Close()
+	mock.random = newLockedRand(7)
+
+	mock.GetOrdersCount()
+
+	sleeps := clock.Sleeps()
+	assert.Equal(t, 6, len(sleeps))
+	for attempt, sleep := range sleeps {
+		assert.T(t, sleep >= 0 && sleep < backoff(attempt), attempt, sleep)
+	}
+}
+
+// Should let a full bucket through and then pace at the leak rate
+func TestLimiterTiming(t *testing.T) {
+	clock := newFakeClock()
+	bucket := newLimiter(bucketSize, leakRate, clock)
+
+	for i := 0; i < bucketSize; i++ {
+		bucket.wait()
+	}
+	assert.Equal(t, 0, len(clock.Sleeps()))
+
+	bucket.wait()
+	bucket.wait()
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}, clock.Sleeps())
+}
+
+// Should catch up with the usage reported by shopify
+func TestLimiterObserve(t *testing.T) {
+	clock := newFakeClock()
+	bucket := newLimiter(bucketSize, leakRate, clock)
+
+	bucket.observe("40/40")
+	bucket.wait()
+
+	assert.Equal(t, []time.Duration{500 * time.Millisecond}, clock.Sleeps())
+}
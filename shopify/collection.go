@@ -0,0 +1,77 @@
+package shopify
+
+import "fmt"
+
+// smartCollectionColumns are the product properties a smart collection rule can match on
+var smartCollectionColumns = map[string]bool{
+	"title":                    true,
+	"type":                     true,
+	"vendor":                   true,
+	"variant_title":            true,
+	"variant_compare_at_price": true,
+	"variant_weight":           true,
+	"variant_inventory":        true,
+	"variant_price":            true,
+	"tag":                      true,
+	"is_price_reduced":         true,
+}
+
+// smartCollectionRelations are the comparisons a smart collection rule can apply
+var smartCollectionRelations = map[string]bool{
+	"equals":       true,
+	"not_equals":   true,
+	"greater_than": true,
+	"less_than":    true,
+	"starts_with":  true,
+	"ends_with":    true,
+	"contains":     true,
+	"not_contains": true,
+	"is_set":       true,
+	"is_not_set":   true,
+}
+
+//SetSmartCollectionRules replaces the rules of a smart collection in the given order, a disjunctive
+//collection includes the products matching any rule instead of all of them
+func (shop *Shopify) SetSmartCollectionRules(collectionID int64, rules []CollectionRule, disjunctive bool) (*SmartCollection, []error) {
+	for i, rule := range rules {
+		if err := validateCollectionRule(rule); err != nil {
+			return nil, []error{fmt.Errorf("rule %d: %w", i, err)}
+		}
+	}
+	var collectionResponse SmartCollectionResponse
+	body := map[string]interface{}{
+		"id":          collectionID,
+		"rules":       rules,
+		"disjunctive": disjunctive,
+	}
+	response, errors := shop.Put(fmt.Sprintf("smart_collections/%v", collectionID), map[string]interface{}{"smart_collection": body})
+	if err := unmarshal(response, errors, &collectionResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &collectionResponse.SmartCollection, nil
+}
+
+//CountCollectionProducts returns the number of products in a custom or smart collection.
+//Collections have no count endpoint of their own, so it counts the products filtered by collection.
+func (shop *Shopify) CountCollectionProducts(collectionID int64) (int, []error) {
+	var productsCount CountResponse
+	response, errors := shop.GetWithParameters("products/count", map[string]string{"collection_id": fmt.Sprint(collectionID)})
+	if err := unmarshal(response, errors, &productsCount); len(err) > 0 {
+		return 0, err
+	}
+	return productsCount.Count, nil
+}
+
+// validateCollectionRule checks the rule's column and relation are known to shopify and it has a condition
+func validateCollectionRule(rule CollectionRule) error {
+	if !smartCollectionColumns[rule.Column] {
+		return fmt.Errorf("invalid smart collection rule column %q", rule.Column)
+	}
+	if !smartCollectionRelations[rule.Relation] {
+		return fmt.Errorf("invalid smart collection rule relation %q", rule.Relation)
+	}
+	if rule.Condition == "" {
+		return fmt.Errorf("smart collection rule on %q has no condition", rule.Column)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should PUT the rules in the given order along with the disjunctive flag
+func TestSetSmartCollectionRules(t *testing.T) {
+	fixture := loadFixture(t, "smart_collection.json")
+	var body map[string]map[string]interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/admin/smart_collections/482865238.json", r.URL.Path)
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write(fixture)
+	})
+	defer server.Close()
+
+	collection, errs := mock.SetSmartCollectionRules(482865238, []CollectionRule{
+		{Column: "vendor", Relation: "equals", Condition: "Apple"},
+		{Column: "type", Relation: "equals", Condition: "Cult Products"},
+	}, true)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, true, body["smart_collection"]["disjunctive"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"column": "vendor", "relation": "equals", "condition": "Apple"},
+		map[string]interface{}{"column": "type", "relation": "equals", "condition": "Cult Products"},
+	}, body["smart_collection"]["rules"])
+	assert.T(t, collection.Disjunctive)
+	assert.Equal(t, 2, len(collection.Rules))
+}
+
+// Should reject unknown columns and relations without calling shopify
+func TestSetSmartCollectionRulesInvalid(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %v", r.URL.Path)
+	})
+	defer server.Close()
+
+	for _, rule := range []CollectionRule{
+		{Column: "color", Relation: "equals", Condition: "red"},
+		{Column: "vendor", Relation: "like", Condition: "Apple"},
+		{Column: "vendor", Relation: "equals"},
+	} {
+		_, errs := mock.SetSmartCollectionRules(482865238, []CollectionRule{rule}, false)
+		assert.Equal(t, 1, len(errs))
+	}
+}
+
+// Should count the products filtered by collection
+func TestCountCollectionProducts(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/products/count.json", r.URL.Path)
+		assert.Equal(t, "482865238", r.URL.Query().Get("collection_id"))
+		w.Write([]byte(`{"count": 4}`))
+	})
+	defer server.Close()
+
+	count, errs := mock.CountCollectionProducts(482865238)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 4, count)
+}
@@ -0,0 +1,284 @@
+package shopify
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Canadian and european tax exemptions, the US reseller ones are built from usStates
+var taxExemptions = map[string]bool{
+	"CA_STATUS_CARD_EXEMPTION":                 true,
+	"CA_DIPLOMAT_EXEMPTION":                    true,
+	"CA_BC_RESELLER_EXEMPTION":                 true,
+	"CA_MB_RESELLER_EXEMPTION":                 true,
+	"CA_SK_RESELLER_EXEMPTION":                 true,
+	"CA_BC_COMMERCIAL_FISHERY_EXEMPTION":       true,
+	"CA_MB_COMMERCIAL_FISHERY_EXEMPTION":       true,
+	"CA_NS_COMMERCIAL_FISHERY_EXEMPTION":       true,
+	"CA_PE_COMMERCIAL_FISHERY_EXEMPTION":       true,
+	"CA_SK_COMMERCIAL_FISHERY_EXEMPTION":       true,
+	"CA_BC_PRODUCTION_AND_MACHINERY_EXEMPTION": true,
+	"CA_SK_PRODUCTION_AND_MACHINERY_EXEMPTION": true,
+	"CA_BC_SUB_CONTRACTOR_EXEMPTION":           true,
+	"CA_SK_SUB_CONTRACTOR_EXEMPTION":           true,
+	"CA_BC_CONTRACTOR_EXEMPTION":               true,
+	"CA_SK_CONTRACTOR_EXEMPTION":               true,
+	"CA_ON_PURCHASE_EXEMPTION":                 true,
+	"CA_MB_FARMER_EXEMPTION":                   true,
+	"CA_NS_FARMER_EXEMPTION":                   true,
+	"CA_SK_FARMER_EXEMPTION":                   true,
+	"EU_REVERSE_CHARGE_EXEMPTION_RULE":         true,
+}
+
+var usStates = "AK AL AR AZ CA CO CT DC DE FL GA HI IA ID IL IN KS KY LA MA MD ME MI MN MO MS MT NC ND NE NH NJ NM NV NY OH OK OR PA RI SC SD TN TX UT VA VT WA WI WV WY"
+
+func init() {
+	for _, state := range strings.Fields(usStates) {
+		taxExemptions[fmt.Sprintf("US_%v_RESELLER_EXEMPTION", state)] = true
+	}
+}
+
+//GetCustomer returns a customer given its id
+func (shop *Shopify) GetCustomer(customerID int64) (*Customer, []error) {
+	var customerResponse CustomerResponse
+	response, errors := shop.Get(fmt.Sprintf("customers/%v", customerID))
+	if err := unmarshal(response, errors, &customerResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &customerResponse.Customer, nil
+}
+
+//CreateCustomer creates a customer, its email is trimmed and lowercased and obviously invalid
+//addresses are rejected before calling shopify
+func (shop *Shopify) CreateCustomer(customer Customer) (*Customer, []error) {
+	if customer.Email != "" {
+		email, err := normalizeEmail(customer.Email)
+		if err != nil {
+			return nil, []error{err}
+		}
+		customer.Email = email
+	}
+	var customerResponse CustomerResponse
+	response, errors := shop.Post("customers", map[string]interface{}{"customer": customerBody(customer)})
+	if err := unmarshal(response, errors, &customerResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &customerResponse.Customer, nil
+}
+
+// customerBody keeps the fields of a customer shopify accepts on creation, leaving out the ones it manages
+// on its own like the id, the orders count or the total spent
+func customerBody(customer Customer) map[string]interface{} {
+	body := map[string]interface{}{
+		"accepts_marketing": customer.AcceptsMarketing,
+		"tax_exempt":        customer.TaxExempt,
+	}
+	for key, value := range map[string]string{
+		"email":      customer.Email,
+		"first_name": customer.FirstName,
+		"last_name":  customer.LastName,
+		"note":       customer.Note,
+		"tags":       customer.Tags,
+	} {
+		if value != "" {
+			body[key] = value
+		}
+	}
+	if len(customer.TaxExemptions) > 0 {
+		body["tax_exemptions"] = customer.TaxExemptions
+	}
+	return body
+}
+
+//UpdateCustomer updates an existing customer with the given fields, an email among them is
+//normalized and validated as in CreateCustomer
+func (shop *Shopify) UpdateCustomer(customerID int64, fields map[string]interface{}) (*Customer, []error) {
+	if email, ok := fields["email"].(string); ok && email != "" {
+		normalized, err := normalizeEmail(email)
+		if err != nil {
+			return nil, []error{err}
+		}
+		fields["email"] = normalized
+	}
+	return shop.updateCustomer(customerID, fields)
+}
+
+//SetCustomerTaxExemptions replaces the customer's tax exemptions, e.g. CA_STATUS_CARD_EXEMPTION.
+//Unknown exemptions are rejected before calling shopify.
+func (shop *Shopify) SetCustomerTaxExemptions(customerID int64, exemptions []string) (*Customer, []error) {
+	var errs []error
+	for _, exemption := range exemptions {
+		if !taxExemptions[exemption] {
+			errs = append(errs, fmt.Errorf("unknown tax exemption %q", exemption))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if exemptions == nil {
+		exemptions = []string{}
+	}
+	return shop.updateCustomer(customerID, map[string]interface{}{"tax_exemptions": exemptions})
+}
+
+//ErrCustomerAlreadyEnabled is returned by SendCustomerInvite when the customer already activated their account
+var ErrCustomerAlreadyEnabled = errors.New("customer account is already enabled")
+
+//SendCustomerInvite emails the customer an invite to activate their account. The subject and custom message
+//replace the ones of the store's invite template unless empty. A customer whose account is already enabled
+//gets no invite and ErrCustomerAlreadyEnabled is returned.
+func (shop *Shopify) SendCustomerInvite(customerID int64, subject, customMessage string) []error {
+	invite := make(map[string]interface{})
+	if subject != "" {
+		invite["subject"] = subject
+	}
+	if customMessage != "" {
+		invite["custom_message"] = customMessage
+	}
+	_, errors := shop.Post(fmt.Sprintf("customers/%v/send_invite", customerID), map[string]interface{}{"customer_invite": invite})
+	if shopifyError := findShopifyError(errors); shopifyError != nil && isAlreadyEnabledError(shopifyError) {
+		return []error{fmt.Errorf("customer %v: %w", customerID, ErrCustomerAlreadyEnabled)}
+	}
+	return errors
+}
+
+// isAlreadyEnabledError tells whether shopify refused an invite because the account is already enabled
+func isAlreadyEnabledError(shopifyError *ShopifyError) bool {
+	if shopifyError.StatusCode != http.StatusUnprocessableEntity {
+		return false
+	}
+	for _, messages := range shopifyError.Errors {
+		for _, message := range messages {
+			if strings.Contains(strings.ToLower(message), "already enabled") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//ErrCustomerNotFound is returned by GetCustomersByEmails for each email no customer has
+var ErrCustomerNotFound = errors.New("no customer with this email")
+
+//GetCustomersByEmails searches the customers with the given emails concurrently and returns them keyed by
+//their lowercased email. Each email without a customer is reported as an error wrapping ErrCustomerNotFound,
+//the map still holds the customers found.
+func (shop *Shopify) GetCustomersByEmails(emails []string) (map[string]Customer, []error) {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		errs      []error
+		customers = make(map[string]Customer)
+		searched  = make(map[string]bool)
+	)
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" || searched[email] {
+			continue
+		}
+		searched[email] = true
+		wg.Add(1)
+		go func(email string) {
+			defer wg.Done()
+			var customersResponse CustomersResponse
+			response, errors := shop.GetWithParameters("customers/search", map[string]string{"query": "email:" + email})
+			err := unmarshal(response, errors, &customersResponse)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, e := range err {
+				errs = append(errs, fmt.Errorf("customer %v: %w", email, e))
+			}
+			if len(err) > 0 {
+				return
+			}
+			// the search also matches similar emails
+			for _, customer := range customersResponse.Customers {
+				if strings.ToLower(customer.Email) == email {
+					customers[email] = customer
+					return
+				}
+			}
+			errs = append(errs, fmt.Errorf("customer %v: %w", email, ErrCustomerNotFound))
+		}(email)
+	}
+	wg.Wait()
+	return customers, errs
+}
+
+//GetCustomerAddresses returns the addresses of a customer
+func (shop *Shopify) GetCustomerAddresses(customerID int64) ([]CustomerAddress, []error) {
+	var addresses CustomerAddressesResponse
+	response, errors := shop.Get(fmt.Sprintf("customers/%v/addresses", customerID))
+	if err := unmarshal(response, errors, &addresses); len(err) > 0 {
+		return nil, err
+	}
+	return addresses.Addresses, nil
+}
+
+//AddCustomerAddress adds an address to a customer
+func (shop *Shopify) AddCustomerAddress(customerID int64, address Address) (*CustomerAddress, []error) {
+	var addressResponse CustomerAddressResponse
+	response, errors := shop.Post(fmt.Sprintf("customers/%v/addresses", customerID), map[string]interface{}{"address": address})
+	if err := unmarshal(response, errors, &addressResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &addressResponse.CustomerAddress, nil
+}
+
+//UpdateCustomerAddress changes the non blank fields of one of the customer's addresses
+func (shop *Shopify) UpdateCustomerAddress(customerID, addressID int64, address Address) (*CustomerAddress, []error) {
+	var addressResponse CustomerAddressResponse
+	fields := toJSONMap(address)
+	fields["id"] = addressID
+	response, errors := shop.Put(fmt.Sprintf("customers/%v/addresses/%v", customerID, addressID), map[string]interface{}{"address": fields})
+	if err := unmarshal(response, errors, &addressResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &addressResponse.CustomerAddress, nil
+}
+
+//DeleteCustomerAddress removes one of the customer's addresses, shopify refuses to remove the default one
+func (shop *Shopify) DeleteCustomerAddress(customerID, addressID int64) []error {
+	_, errors := shop.Delete(fmt.Sprintf("customers/%v/addresses/%v", customerID, addressID))
+	return errors
+}
+
+//SetDefaultCustomerAddress makes one of the customer's addresses their default one
+func (shop *Shopify) SetDefaultCustomerAddress(customerID, addressID int64) (*CustomerAddress, []error) {
+	var addressResponse CustomerAddressResponse
+	response, errors := shop.Put(fmt.Sprintf("customers/%v/addresses/%v/default", customerID, addressID), nil)
+	if err := unmarshal(response, errors, &addressResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &addressResponse.CustomerAddress, nil
+}
+
+// updateCustomer PUTs the given fields of a customer
+func (shop *Shopify) updateCustomer(customerID int64, fields map[string]interface{}) (*Customer, []error) {
+	var customerResponse CustomerResponse
+	fields["id"] = customerID
+	response, errors := shop.Put(fmt.Sprintf("customers/%v", customerID), map[string]interface{}{"customer": fields})
+	if err := unmarshal(response, errors, &customerResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &customerResponse.Customer, nil
+}
+
+// normalizeEmail trims and lowercases an email, rejecting the ones shopify would refuse anyway:
+// anything without a single local@domain.tld shape or with spaces in it
+func normalizeEmail(email string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(normalized, "@")
+	if at < 1 || strings.ContainsAny(normalized, " \t\r\n") || strings.Count(normalized, "@") > 1 {
+		return "", fmt.Errorf("invalid email %q", email)
+	}
+	host := normalized[at+1:]
+	dot := strings.LastIndex(host, ".")
+	if dot < 1 || dot == len(host)-1 || strings.HasPrefix(host, ".") || strings.Contains(host, "..") {
+		return "", fmt.Errorf("invalid email %q", email)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,217 @@
+package shopify
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should PUT the customer's tax exemptions
+func TestSetCustomerTaxExemptions(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/admin/customers/207119551.json", r.URL.Path)
+
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, float64(207119551), body["customer"]["id"])
+		assert.Equal(t, []interface{}{"CA_STATUS_CARD_EXEMPTION", "US_NY_RESELLER_EXEMPTION"}, body["customer"]["tax_exemptions"])
+
+		w.Write([]byte(`{"customer": {"id": 207119551, "tax_exempt": false,
+			"tax_exemptions": ["CA_STATUS_CARD_EXEMPTION", "US_NY_RESELLER_EXEMPTION"]}}`))
+	})
+	defer server.Close()
+
+	customer, errs := mock.SetCustomerTaxExemptions(207119551, []string{"CA_STATUS_CARD_EXEMPTION", "US_NY_RESELLER_EXEMPTION"})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"CA_STATUS_CARD_EXEMPTION", "US_NY_RESELLER_EXEMPTION"}, customer.TaxExemptions)
+}
+
+// Should reject unknown exemptions without calling shopify
+func TestSetCustomerTaxExemptionsInvalid(t *testing.T) {
+	customer, errs := shop.SetCustomerTaxExemptions(207119551, []string{"US_XX_RESELLER_EXEMPTION", "CA_DIPLOMAT_EXEMPTION", "nonprofit"})
+
+	assert.T(t, customer == nil)
+	assert.Equal(t, 2, len(errs))
+}
+
+// Should send the email trimmed and lowercased along with the writable fields only
+func TestCreateCustomerEmail(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/admin/customers.json", r.URL.Path)
+
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, map[string]interface{}{
+			"email":             "steve.lastnameson@example.com",
+			"first_name":        "Steve",
+			"tags":              "vip",
+			"accepts_marketing": true,
+			"tax_exempt":        false,
+		}, body["customer"])
+		w.WriteHeader(201)
+		w.Write([]byte(`{"customer": {"id": 1073339470, "email": "steve.lastnameson@example.com"}}`))
+	})
+	defer server.Close()
+
+	customer, errs := mock.CreateCustomer(Customer{ID: 42, FirstName: "Steve", Email: "  Steve.Lastnameson@Example.com ", Tags: "vip",
+		AcceptsMarketing: true, OrdersCount: 3, State: "enabled", TotalSpent: Money{Amount: "10.00"}})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, int64(1073339470), customer.ID)
+}
+
+// Should reject invalid emails without calling shopify
+func TestCreateCustomerInvalidEmail(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %v", r.URL.Path)
+	})
+	defer server.Close()
+
+	for _, email := range []string{"steve", "@example.com", "steve@", "steve@example", "steve@example.", "st eve@example.com", "steve@@example.com", "steve@.example.com"} {
+		_, errs := mock.CreateCustomer(Customer{Email: email})
+		assert.Equal(t, 1, len(errs), email)
+	}
+	_, errs := mock.UpdateCustomer(207119551, map[string]interface{}{"email": "steve@example"})
+	assert.Equal(t, 1, len(errs))
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	for email, expected := range map[string]string{
+		"bob.norman@hostmail.com":       "bob.norman@hostmail.com",
+		" Bob.Norman+shop@HostMail.com": "bob.norman+shop@hostmail.com",
+		"o'brien@mail.example.co.uk":    "o'brien@mail.example.co.uk",
+	} {
+		normalized, err := normalizeEmail(email)
+		assert.T(t, err == nil, err)
+		assert.Equal(t, expected, normalized)
+	}
+}
+
+// Should POST the new address of the customer
+func TestAddCustomerAddress(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/admin/customers/207119551/addresses.json", r.URL.Path)
+
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, map[string]interface{}{"address1": "1 Rue des Carrieres", "city": "Montreal", "country": "Canada", "zip": "G1R 4P5"}, body["address"])
+		w.WriteHeader(201)
+		w.Write([]byte(`{"customer_address": {"id": 1053317335, "customer_id": 207119551, "address1": "1 Rue des Carrieres",
+			"city": "Montreal", "country": "Canada", "zip": "G1R 4P5", "default": false}}`))
+	})
+	defer server.Close()
+
+	address, errs := mock.AddCustomerAddress(207119551, Address{Address1: "1 Rue des Carrieres", City: "Montreal", Country: "Canada", Zip: "G1R 4P5"})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, int64(1053317335), address.ID)
+	assert.Equal(t, "Montreal", address.City)
+	assert.T(t, !address.Default)
+}
+
+// Should PUT the default endpoint of the address
+func TestSetDefaultCustomerAddress(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/admin/customers/207119551/addresses/1053317335/default.json", r.URL.Path)
+		w.Write([]byte(`{"customer_address": {"id": 1053317335, "customer_id": 207119551, "default": true}}`))
+	})
+	defer server.Close()
+
+	address, errs := mock.SetDefaultCustomerAddress(207119551, 1053317335)
+
+	assert.T(t, errs == nil, errs)
+	assert.T(t, address.Default)
+}
+
+// Should POST the invite with the custom subject and message, leaving the blank ones to the template
+func TestSendCustomerInvite(t *testing.T) {
+	var invites []map[string]interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/admin/customers/207119551/send_invite.json", r.URL.Path)
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		invites = append(invites, body["customer_invite"])
+		w.WriteHeader(201)
+		w.Write([]byte(`{"customer_invite": {"to": "bob.norman@mail.example.com", "subject": "Welcome to my new shop"}}`))
+	})
+	defer server.Close()
+
+	errs := mock.SendCustomerInvite(207119551, "Welcome to my new shop", "My awesome new store")
+	assert.T(t, errs == nil, errs)
+	errs = mock.SendCustomerInvite(207119551, "", "")
+	assert.T(t, errs == nil, errs)
+
+	assert.Equal(t, []map[string]interface{}{
+		{"subject": "Welcome to my new shop", "custom_message": "My awesome new store"},
+		{},
+	}, invites)
+}
+
+// Should report an already enabled account with ErrCustomerAlreadyEnabled and other failures as they are
+func TestSendCustomerInviteErrors(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/customers/207119551/send_invite.json" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"errors": {"customer": ["Account already enabled"]}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors": "Not Found"}`))
+	})
+	defer server.Close()
+
+	errs := mock.SendCustomerInvite(207119551, "", "")
+	assert.Equal(t, 1, len(errs))
+	assert.T(t, errors.Is(errs[0], ErrCustomerAlreadyEnabled), errs)
+	assert.Equal(t, "customer 207119551: customer account is already enabled", errs[0].Error())
+
+	errs = mock.SendCustomerInvite(1, "", "")
+	assert.Equal(t, http.StatusNotFound, findShopifyError(errs).StatusCode)
+}
+
+// Should search each lowercased email once and report the one no customer has
+func TestGetCustomersByEmails(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/customers/search.json", r.URL.Path)
+		query := r.URL.Query().Get("query")
+		mu.Lock()
+		queries = append(queries, query)
+		mu.Unlock()
+		switch query {
+		case "email:bob.norman@hostmail.com":
+			w.Write([]byte(`{"customers": [
+				{"id": 207119551, "email": "Bob.Norman@hostmail.com", "first_name": "Bob"},
+				{"id": 207119552, "email": "bob.norman@hostmail.co", "first_name": "Robert"}
+			]}`))
+		case "email:jane.doe@example.com":
+			w.Write([]byte(`{"customers": [{"id": 115310627, "email": "jane.doe@example.com", "first_name": "Jane"}]}`))
+		default:
+			w.Write([]byte(`{"customers": []}`))
+		}
+	})
+	defer server.Close()
+
+	customers, errs := mock.GetCustomersByEmails([]string{"Bob.Norman@Hostmail.com", "jane.doe@example.com", "nobody@example.com", "bob.norman@hostmail.com"})
+
+	sort.Strings(queries)
+	assert.Equal(t, []string{"email:bob.norman@hostmail.com", "email:jane.doe@example.com", "email:nobody@example.com"}, queries)
+	assert.Equal(t, 2, len(customers))
+	assert.Equal(t, int64(207119551), customers["bob.norman@hostmail.com"].ID)
+	assert.Equal(t, int64(115310627), customers["jane.doe@example.com"].ID)
+	assert.Equal(t, 1, len(errs))
+	assert.T(t, errors.Is(errs[0], ErrCustomerNotFound), errs)
+	assert.Equal(t, "customer nobody@example.com: no customer with this email", errs[0].Error())
+}
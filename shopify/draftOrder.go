@@ -0,0 +1,71 @@
+package shopify
+
+import "fmt"
+
+//GetDraftOrder returns a draft order by id
+func (shop *Shopify) GetDraftOrder(draftID int64) (*DraftOrder, []error) {
+	var draftResponse DraftOrderResponse
+	response, errors := shop.Get(fmt.Sprintf("draft_orders/%v", draftID))
+	if err := unmarshal(response, errors, &draftResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &draftResponse.DraftOrder, nil
+}
+
+//AddDraftOrderLineItem appends a line item to the ones the draft order already has. Items without a
+//variant are custom line items and need a title and a price.
+func (shop *Shopify) AddDraftOrderLineItem(draftID int64, item DraftOrderLineItem) (*DraftOrder, []error) {
+	if err := validateDraftOrderLineItem(item); err != nil {
+		return nil, []error{err}
+	}
+	draft, errs := shop.GetDraftOrder(draftID)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	var lineItems []map[string]interface{}
+	for _, existing := range append(draft.LineItems, item) {
+		lineItems = append(lineItems, draftOrderLineItemBody(existing))
+	}
+	var draftResponse DraftOrderResponse
+	body := map[string]interface{}{"id": draftID, "line_items": lineItems}
+	response, errors := shop.Put(fmt.Sprintf("draft_orders/%v", draftID), map[string]interface{}{"draft_order": body})
+	if err := unmarshal(response, errors, &draftResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &draftResponse.DraftOrder, nil
+}
+
+// validateDraftOrderLineItem checks the item either references a variant or has what a custom item needs
+func validateDraftOrderLineItem(item DraftOrderLineItem) error {
+	if item.Quantity < 1 {
+		return fmt.Errorf("draft order line item needs a positive quantity")
+	}
+	if item.VariantID == nil && (item.Title == "" || item.Price.Amount == "") {
+		return fmt.Errorf("custom draft order line item needs a title and a price")
+	}
+	return nil
+}
+
+// draftOrderLineItemBody keeps the fields shopify accepts when updating the line items of a draft order,
+// so that re-sending the existing items keeps their discounts and properties
+func draftOrderLineItemBody(item DraftOrderLineItem) map[string]interface{} {
+	body := map[string]interface{}{"quantity": item.Quantity}
+	if item.VariantID != nil {
+		body["variant_id"] = *item.VariantID
+	} else {
+		body["title"] = item.Title
+		body["price"] = item.Price.Amount
+		body["taxable"] = item.Taxable
+		body["requires_shipping"] = item.RequiresShipping
+		if item.Grams > 0 {
+			body["grams"] = item.Grams
+		}
+	}
+	if item.AppliedDiscount != nil {
+		body["applied_discount"] = item.AppliedDiscount
+	}
+	if len(item.Properties) > 0 {
+		body["properties"] = item.Properties
+	}
+	return body
+}
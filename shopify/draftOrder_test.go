@@ -0,0 +1,74 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should PUT the existing line items, keeping their discounts and properties, followed by the new custom one
+func TestAddDraftOrderLineItem(t *testing.T) {
+	fixture := loadFixture(t, "draft_order.json")
+	puts := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/draft_orders/994118539.json", r.URL.Path)
+		if r.Method == "PUT" {
+			puts++
+			var body map[string]map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, []interface{}{
+				map[string]interface{}{"variant_id": float64(39072856), "quantity": float64(2)},
+				map[string]interface{}{
+					"variant_id": float64(49148385),
+					"quantity":   float64(1),
+					"applied_discount": map[string]interface{}{
+						"title":       "Loyalty",
+						"description": "Returning customer",
+						"value":       "10.0",
+						"value_type":  "percentage",
+						"amount":      "19.90",
+					},
+					"properties": []interface{}{map[string]interface{}{"name": "engraving", "value": "Happy birthday"}},
+				},
+				map[string]interface{}{
+					"title":             "Engraving",
+					"price":             "15.00",
+					"quantity":          float64(1),
+					"taxable":           true,
+					"requires_shipping": false,
+				},
+			}, body["draft_order"]["line_items"])
+		}
+		w.Write(fixture)
+	})
+	defer server.Close()
+
+	_, errs := mock.AddDraftOrderLineItem(994118539, DraftOrderLineItem{
+		Title:    "Engraving",
+		Price:    Money{Amount: "15.00"},
+		Quantity: 1,
+		Taxable:  true,
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 1, puts)
+}
+
+// Should reject custom line items missing a title or a price without calling shopify
+func TestAddDraftOrderLineItemInvalid(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %v", r.URL.Path)
+	})
+	defer server.Close()
+
+	for _, item := range []DraftOrderLineItem{
+		{Title: "Engraving", Quantity: 1},
+		{Price: Money{Amount: "15.00"}, Quantity: 1},
+		{Title: "Engraving", Price: Money{Amount: "15.00"}},
+	} {
+		_, errs := mock.AddDraftOrderLineItem(994118539, item)
+		assert.Equal(t, 1, len(errs))
+	}
+}
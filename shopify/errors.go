@@ -0,0 +1,175 @@
+package shopify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// baseErrorKey is the key ErrorMessages files the errors that aren't about a specific field under
+const baseErrorKey = "base"
+
+// ShopifyError is the error returned when shopify answers with a non 2xx status code.
+// Errors holds the decoded "errors" field of the response body, when present.
+type ShopifyError struct {
+	StatusCode int           `json:"-"`
+	Errors     ErrorMessages `json:"errors"`
+}
+
+func (e *ShopifyError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("shopify: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("shopify: %d %v", e.StatusCode, e.Errors)
+}
+
+// ErrorMessages are the messages of shopify's "errors" field keyed by the field they are about.
+// Shopify sends them as a string, a list or an object, the first two are filed under "base".
+type ErrorMessages map[string][]string
+
+//UnmarshalJSON normalizes the string, list and object forms of the errors field
+func (m *ErrorMessages) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	messages := make(ErrorMessages)
+	switch errors := raw.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case map[string]interface{}:
+		for field, value := range errors {
+			messages[field] = errorStrings(value)
+		}
+	default:
+		messages[baseErrorKey] = errorStrings(errors)
+	}
+	*m = messages
+	return nil
+}
+
+func (m ErrorMessages) String() string {
+	fields := make([]string, 0, len(m))
+	for field := range m {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	var parts []string
+	for _, field := range fields {
+		message := strings.Join(m[field], ", ")
+		if field != baseErrorKey {
+			message = field + ": " + message
+		}
+		parts = append(parts, message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// errorStrings flattens one value of the errors field into its messages
+func errorStrings(value interface{}) []string {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{value}
+	case []interface{}:
+		var messages []string
+		for _, item := range value {
+			messages = append(messages, errorStrings(item)...)
+		}
+		return messages
+	case map[string]interface{}:
+		// GraphQL errors are objects with a message
+		if message, ok := value["message"].(string); ok {
+			return []string{message}
+		}
+		encoded, _ := json.Marshal(value)
+		return []string{string(encoded)}
+	default:
+		encoded, _ := json.Marshal(value)
+		return []string{string(encoded)}
+	}
+}
+
+// newShopifyError builds a ShopifyError from the status code and body of a failed response
+func newShopifyError(statusCode int, body []byte) *ShopifyError {
+	shopifyError := &ShopifyError{StatusCode: statusCode}
+	json.Unmarshal(body, shopifyError)
+	return shopifyError
+}
+
+// scopePattern matches the access scopes named in an error message, e.g. read_orders
+var scopePattern = regexp.MustCompile(`\b(?:unauthenticated_)?(?:read|write)_[a-z_]+\b`)
+
+//ScopeError is the error returned when shopify answers 403 because the app lacks access scopes, naming
+//the scopes the app must ask the merchant for. It wraps the ShopifyError of the response.
+type ScopeError struct {
+	*ShopifyError
+	// Scopes are the missing access scopes, e.g. read_orders
+	Scopes []string
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("shopify: %d missing access scopes %v", e.StatusCode, strings.Join(e.Scopes, ", "))
+}
+
+//Unwrap returns the ShopifyError of the response, so that errors.As finds it
+func (e *ScopeError) Unwrap() error {
+	return e.ShopifyError
+}
+
+// scopeError turns a 403 naming the missing access scopes into a ScopeError, other errors are returned as they are
+func scopeError(shopifyError *ShopifyError) error {
+	if shopifyError.StatusCode != http.StatusForbidden {
+		return shopifyError
+	}
+	seen := make(map[string]bool)
+	var scopes []string
+	for _, messages := range shopifyError.Errors {
+		for _, message := range messages {
+			for _, scope := range scopePattern.FindAllString(message, -1) {
+				if !seen[scope] {
+					seen[scope] = true
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+	}
+	if len(scopes) == 0 {
+		return shopifyError
+	}
+	sort.Strings(scopes)
+	return &ScopeError{ShopifyError: shopifyError, Scopes: scopes}
+}
+
+//IsErrorResponse tells whether a response is an error, either by its status code or because its body
+//has an "errors" key, and returns the parsed error if so
+func IsErrorResponse(statusCode int, body []byte) (bool, *ShopifyError) {
+	if statusCode < 200 || statusCode > 299 {
+		return true, newShopifyError(statusCode, body)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return false, nil
+	}
+	if errors, ok := fields["errors"]; ok && string(errors) != "null" {
+		return true, newShopifyError(statusCode, body)
+	}
+	return false, nil
+}
+
+// findShopifyError returns the first *ShopifyError found in errs, if any, including the one wrapped by a ScopeError
+func findShopifyError(errs []error) *ShopifyError {
+	for _, err := range errs {
+		var shopifyError *ShopifyError
+		if errors.As(err, &shopifyError) {
+			return shopifyError
+		}
+	}
+	return nil
+}
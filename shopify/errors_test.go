@@ -0,0 +1,97 @@
+package shopify
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should not flag a successful body
+func TestIsErrorResponseSuccess(t *testing.T) {
+	isError, shopifyError := IsErrorResponse(200, []byte(`{"order": {"id": 450789469}}`))
+
+	assert.T(t, !isError)
+	assert.T(t, shopifyError == nil)
+}
+
+// Should parse the errors object of a 422
+func TestIsErrorResponseUnprocessable(t *testing.T) {
+	isError, shopifyError := IsErrorResponse(422, []byte(`{"errors": {"title": ["can't be blank"]}}`))
+
+	assert.T(t, isError)
+	assert.Equal(t, 422, shopifyError.StatusCode)
+	assert.Equal(t, ErrorMessages{"title": {"can't be blank"}}, shopifyError.Errors)
+}
+
+// Should flag a body with a string errors field even when the status is a success
+func TestIsErrorResponseString(t *testing.T) {
+	isError, shopifyError := IsErrorResponse(200, []byte(`{"errors": "Not found"}`))
+
+	assert.T(t, isError)
+	assert.Equal(t, ErrorMessages{"base": {"Not found"}}, shopifyError.Errors)
+
+	isError, shopifyError = IsErrorResponse(404, []byte(`{"errors": "Not found"}`))
+	assert.T(t, isError)
+	assert.Equal(t, 404, shopifyError.StatusCode)
+}
+
+// Should file a string errors field under the base key
+func TestErrorMessagesString(t *testing.T) {
+	var shopifyError ShopifyError
+	err := json.Unmarshal([]byte(`{"errors": "Not Found"}`), &shopifyError)
+
+	assert.T(t, err == nil, err)
+	assert.Equal(t, ErrorMessages{"base": {"Not Found"}}, shopifyError.Errors)
+	assert.Equal(t, "Not Found", shopifyError.Errors.String())
+}
+
+// Should keep the messages of an errors object by field
+func TestErrorMessagesObject(t *testing.T) {
+	var shopifyError ShopifyError
+	err := json.Unmarshal([]byte(`{"errors": {"title": ["can't be blank"], "price": "is invalid"}}`), &shopifyError)
+
+	assert.T(t, err == nil, err)
+	assert.Equal(t, ErrorMessages{"title": {"can't be blank"}, "price": {"is invalid"}}, shopifyError.Errors)
+	assert.Equal(t, "price: is invalid; title: can't be blank", shopifyError.Errors.String())
+}
+
+// Should file an errors list under the base key
+func TestErrorMessagesList(t *testing.T) {
+	var shopifyError ShopifyError
+	err := json.Unmarshal([]byte(`{"errors": ["base error", "another error"]}`), &shopifyError)
+
+	assert.T(t, err == nil, err)
+	assert.Equal(t, ErrorMessages{"base": {"base error", "another error"}}, shopifyError.Errors)
+
+	shopifyError.StatusCode = 422
+	assert.Equal(t, "shopify: 422 base error, another error", shopifyError.Error())
+}
+
+// Should report a 403 naming the missing scopes as a ScopeError still holding the ShopifyError
+func TestScopeError(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		if r.URL.Path == "/admin/orders.json" {
+			w.Write([]byte(`{"errors": "[API] This action requires merchant approval for read_orders, read_all_orders scope."}`))
+			return
+		}
+		w.Write([]byte(`{"errors": "[API] This action is forbidden."}`))
+	})
+	defer server.Close()
+
+	_, errs := mock.GetOrders(nil)
+	assert.Equal(t, 1, len(errs))
+	var scoped *ScopeError
+	assert.T(t, errors.As(errs[0], &scoped), errs)
+	assert.Equal(t, []string{"read_all_orders", "read_orders"}, scoped.Scopes)
+	assert.Equal(t, "shopify: 403 missing access scopes read_all_orders, read_orders", errs[0].Error())
+	assert.Equal(t, http.StatusForbidden, findShopifyError(errs).StatusCode)
+
+	_, errs = mock.GetProducts()
+	assert.T(t, !errors.As(errs[0], &scoped), errs)
+	assert.Equal(t, http.StatusForbidden, findShopifyError(errs).StatusCode)
+	assert.Equal(t, "shopify: 403 [API] This action is forbidden.", errs[0].Error())
+}
@@ -0,0 +1,213 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// graphQLResponse is the envelope of every GraphQL answer
+type graphQLResponse struct {
+	Data       json.RawMessage `json:"data"`
+	Extensions struct {
+		Cost struct {
+			RequestedQueryCost int  `json:"requestedQueryCost"`
+			ActualQueryCost    *int `json:"actualQueryCost"`
+			ThrottleStatus     struct {
+				MaximumAvailable   float64 `json:"maximumAvailable"`
+				CurrentlyAvailable int     `json:"currentlyAvailable"`
+				RestoreRate        float64 `json:"restoreRate"`
+			} `json:"throttleStatus"`
+		} `json:"cost"`
+	} `json:"extensions"`
+}
+
+// graphQLUserError is an input rejected by a mutation, reported in its userErrors field
+type graphQLUserError struct {
+	Field   []string `json:"field"`
+	Message string   `json:"message"`
+}
+
+// userErrorsToShopifyError gathers the userErrors of a mutation into a ShopifyError keyed by their field path,
+// the ones without a field are filed under "base"
+func userErrorsToShopifyError(userErrors []graphQLUserError) *ShopifyError {
+	messages := make(ErrorMessages)
+	for _, userError := range userErrors {
+		field := baseErrorKey
+		if len(userError.Field) > 0 {
+			field = strings.Join(userError.Field, ".")
+		}
+		messages[field] = append(messages[field], userError.Message)
+	}
+	return &ShopifyError{StatusCode: http.StatusOK, Errors: messages}
+}
+
+// findUserErrors returns the userErrors reported by the mutations of the response data, if any
+func findUserErrors(data json.RawMessage) []graphQLUserError {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	var userErrors []graphQLUserError
+	for _, field := range fields {
+		var payload struct {
+			UserErrors []graphQLUserError `json:"userErrors"`
+		}
+		if err := json.Unmarshal(field, &payload); err == nil {
+			userErrors = append(userErrors, payload.UserErrors...)
+		}
+	}
+	return userErrors
+}
+
+// GlobalID Returns the GraphQL global id of a resource given its type and REST id.
+// Usage: shopify.GlobalID("Order", 450789469) returns "gid://shopify/Order/450789469"
+func GlobalID(resource string, id int64) string {
+	return fmt.Sprintf("gid://shopify/%s/%d", resource, id)
+}
+
+// graphQLCost keeps the cost reported by the last GraphQL query
+type graphQLCost struct {
+	mu        sync.Mutex
+	requested int
+	actual    int
+	available int
+}
+
+// GraphQL Makes a POST request to the GraphQL admin endpoint with the given query and variables.
+// Both the top-level errors of the response and the userErrors of its mutations are returned as a
+// *ShopifyError, along with the body so that the data can still be read.
+// Usage: shopify.GraphQL("{ shop { name } }", nil)
+func (shopify *Shopify) GraphQL(query string, variables map[string]interface{}) ([]byte, []error) {
+	data := map[string]interface{}{"query": query}
+	if variables != nil {
+		data["variables"] = variables
+	}
+	_, body, errs := shopify.send("POST", shopify.createGraphQLURL(), data)
+	var response graphQLResponse
+	decodeErr := json.Unmarshal(body, &response)
+	// a throttled query is answered with errors along with its cost, which tells how long to back off
+	if decodeErr == nil && (len(errs) == 0 || response.Extensions.Cost.RequestedQueryCost > 0) {
+		shopify.recordGraphQLCost(response)
+	}
+	if len(errs) > 0 {
+		return body, errs
+	}
+	if decodeErr == nil {
+		if userErrors := findUserErrors(response.Data); len(userErrors) > 0 {
+			return body, []error{userErrorsToShopifyError(userErrors)}
+		}
+	}
+	return body, nil
+}
+
+// LastGraphQLCost Returns the requested and actual cost of the last GraphQL query along with the
+// points currently available in the bucket, so that callers can pace expensive queries.
+func (shopify *Shopify) LastGraphQLCost() (requested, actual, available int) {
+	if shopify.graphQLCost == nil {
+		return 0, 0, 0
+	}
+	shopify.graphQLCost.mu.Lock()
+	defer shopify.graphQLCost.mu.Unlock()
+	return shopify.graphQLCost.requested, shopify.graphQLCost.actual, shopify.graphQLCost.available
+}
+
+// PaginateConnection Runs the query until the connection it lists is exhausted and returns the
+// nodes of every page. extract reads the data of each page and returns its nodes, whether there is
+// a next page and the cursor to pass as the "after" variable to get it.
+// Usage: shopify.PaginateConnection(query, map[string]interface{}{"first": 50}, extract)
+func (shopify *Shopify) PaginateConnection(query string, variables map[string]interface{},
+	extract func(data []byte) (items []json.RawMessage, hasNext bool, endCursor string)) ([]json.RawMessage, []error) {
+	// the caller's variables are left untouched
+	pageVariables := make(map[string]interface{}, len(variables)+1)
+	for name, value := range variables {
+		pageVariables[name] = value
+	}
+	var items []json.RawMessage
+	for {
+		var data json.RawMessage
+		if errs := shopify.graphQL(query, pageVariables, &data); len(errs) > 0 {
+			return nil, errs
+		}
+		page, hasNext, endCursor := extract(data)
+		items = append(items, page...)
+		if !hasNext {
+			return items, nil
+		}
+		// asking for the same page again would loop for ever, e.g. when the query doesn't select endCursor
+		if endCursor == "" || endCursor == pageVariables["after"] {
+			return nil, []error{fmt.Errorf("connection has a next page but no new end cursor %q", endCursor)}
+		}
+		pageVariables["after"] = endCursor
+	}
+}
+
+// graphQLConnection is a page of a GraphQL connection with its nodes left undecoded
+type graphQLConnection struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Edges []struct {
+		Node json.RawMessage `json:"node"`
+	} `json:"edges"`
+}
+
+// connectionAt returns an extract function for PaginateConnection reading the connection found by
+// following path from the data, e.g. "order", "events". A missing or null field ends the pagination.
+func connectionAt(path ...string) func(data []byte) ([]json.RawMessage, bool, string) {
+	return func(data []byte) ([]json.RawMessage, bool, string) {
+		for _, field := range path {
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(data, &fields); err != nil {
+				return nil, false, ""
+			}
+			data = fields[field]
+		}
+		var connection graphQLConnection
+		if err := json.Unmarshal(data, &connection); err != nil {
+			return nil, false, ""
+		}
+		nodes := make([]json.RawMessage, len(connection.Edges))
+		for i, edge := range connection.Edges {
+			nodes[i] = edge.Node
+		}
+		return nodes, connection.PageInfo.HasNextPage, connection.PageInfo.EndCursor
+	}
+}
+
+// graphQL runs the query and decodes the data of the response into output. The data is decoded even
+// when errors are returned, since shopify still answers the fields that didn't fail.
+func (shopify *Shopify) graphQL(query string, variables map[string]interface{}, output interface{}) []error {
+	var response graphQLResponse
+	body, errs := shopify.GraphQL(query, variables)
+	if err := json.Unmarshal(body, &response); err != nil {
+		if len(errs) > 0 {
+			return errs
+		}
+		return []error{err}
+	}
+	if output != nil && len(response.Data) > 0 && string(response.Data) != "null" {
+		if err := json.Unmarshal(response.Data, output); err != nil && len(errs) == 0 {
+			return []error{err}
+		}
+	}
+	return errs
+}
+
+func (shopify *Shopify) recordGraphQLCost(response graphQLResponse) {
+	if shopify.graphQLCost == nil {
+		return
+	}
+	cost := response.Extensions.Cost
+	shopify.graphQLCost.mu.Lock()
+	defer shopify.graphQLCost.mu.Unlock()
+	shopify.graphQLCost.requested = cost.RequestedQueryCost
+	shopify.graphQLCost.actual = 0
+	if cost.ActualQueryCost != nil {
+		shopify.graphQLCost.actual = *cost.ActualQueryCost
+	}
+	shopify.graphQLCost.available = cost.ThrottleStatus.CurrentlyAvailable
+}
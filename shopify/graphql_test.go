@@ -0,0 +1,171 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should post the query and keep the cost reported in the extensions
+func TestGraphQLCost(t *testing.T) {
+	fixture := fixtureHandler(t, "/admin/api/graphql.json", "graphql_shop.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "{ shop { name } }", body["query"])
+		fixture(w, r)
+	})
+	defer server.Close()
+
+	var data struct {
+		Shop struct {
+			Name string `json:"name"`
+		} `json:"shop"`
+	}
+	errs := mock.graphQL("{ shop { name } }", nil, &data)
+	requested, actual, available := mock.LastGraphQLCost()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "Apple Computers", data.Shop.Name)
+	assert.Equal(t, 12, requested)
+	assert.Equal(t, 3, actual)
+	assert.Equal(t, 997, available)
+}
+
+// Should record the cost of a throttled query even though it is reported as an error
+func TestGraphQLThrottledCost(t *testing.T) {
+	throttled := false
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if !throttled {
+			throttled = true
+			w.Write([]byte(`{"data": {"shop": {"name": "Apple Computers"}}, "extensions": {"cost": {"requestedQueryCost": 12,
+				"actualQueryCost": 12, "throttleStatus": {"maximumAvailable": 1000.0, "currentlyAvailable": 988, "restoreRate": 50.0}}}}`))
+			return
+		}
+		w.Write([]byte(`{"errors": [{"message": "Throttled", "extensions": {"code": "THROTTLED"}}],
+			"extensions": {"cost": {"requestedQueryCost": 752, "actualQueryCost": null,
+				"throttleStatus": {"maximumAvailable": 1000.0, "currentlyAvailable": 40, "restoreRate": 50.0}}}}`))
+	})
+	defer server.Close()
+
+	_, errs := mock.GraphQL("{ shop { name } }", nil)
+	assert.T(t, errs == nil, errs)
+
+	_, errs = mock.GraphQL("{ products(first: 250) { edges { node { id } } } }", nil)
+	requested, actual, available := mock.LastGraphQLCost()
+
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, 752, requested)
+	assert.Equal(t, 0, actual)
+	assert.Equal(t, 40, available)
+}
+
+// Should return the top-level errors of a query as a ShopifyError
+func TestGraphQLErrors(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors": [{"message": "Field 'nam' doesn't exist on type 'Shop'", "locations": [{"line": 1, "column": 10}]}]}`))
+	})
+	defer server.Close()
+
+	var data struct{}
+	errs := mock.graphQL("{ shop { nam } }", nil, &data)
+
+	assert.Equal(t, 1, len(errs))
+	shopifyError := findShopifyError(errs)
+	assert.T(t, shopifyError != nil, errs)
+	assert.Equal(t, http.StatusOK, shopifyError.StatusCode)
+	assert.Equal(t, ErrorMessages{"base": {"Field 'nam' doesn't exist on type 'Shop'"}}, shopifyError.Errors)
+}
+
+// Should return the userErrors of a mutation as a ShopifyError and still decode its data
+func TestGraphQLUserErrors(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"tagsAdd": {"node": {"id": "gid://shopify/Order/1"}, "userErrors": [
+			{"field": ["tags", "0"], "message": "Tag is too long"},
+			{"field": null, "message": "Order is archived"}
+		]}}}`))
+	})
+	defer server.Close()
+
+	var data struct {
+		TagsAdd struct {
+			Node struct {
+				ID string `json:"id"`
+			} `json:"node"`
+		} `json:"tagsAdd"`
+	}
+	_, rawErrs := mock.GraphQL(`mutation { tagsAdd(id: "gid://shopify/Order/1", tags: ["x"]) { node { id } userErrors { field message } } }`, nil)
+	errs := mock.graphQL(`mutation { tagsAdd(id: "gid://shopify/Order/1", tags: ["x"]) { node { id } userErrors { field message } } }`, nil, &data)
+
+	assert.Equal(t, 1, len(rawErrs))
+	assert.Equal(t, 1, len(errs))
+	shopifyError := findShopifyError(errs)
+	assert.T(t, shopifyError != nil, errs)
+	assert.Equal(t, http.StatusOK, shopifyError.StatusCode)
+	assert.Equal(t, ErrorMessages{"tags.0": {"Tag is too long"}, "base": {"Order is archived"}}, shopifyError.Errors)
+	assert.Equal(t, "gid://shopify/Order/1", data.TagsAdd.Node.ID)
+}
+
+// Should follow the end cursor of each page until the connection is exhausted
+func TestPaginateConnection(t *testing.T) {
+	var cursors []interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "status:open", body["variables"]["query"])
+		cursors = append(cursors, body["variables"]["after"])
+		if body["variables"]["after"] == nil {
+			w.Write([]byte(`{"data": {"orders": {"pageInfo": {"hasNextPage": true, "endCursor": "cursor-1"},
+				"edges": [{"node": {"name": "#1001"}}, {"node": {"name": "#1002"}}]}}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"orders": {"pageInfo": {"hasNextPage": false, "endCursor": "cursor-2"},
+			"edges": [{"node": {"name": "#1003"}}]}}}`))
+	})
+	defer server.Close()
+
+	variables := map[string]interface{}{"query": "status:open"}
+	query := `query($query: String, $after: String) { orders(first: 2, query: $query, after: $after) { pageInfo { hasNextPage endCursor } edges { node { name } } } }`
+	nodes, errs := mock.PaginateConnection(query, variables, connectionAt("orders"))
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []interface{}{nil, "cursor-1"}, cursors)
+	var names []string
+	for _, node := range nodes {
+		var order struct {
+			Name string `json:"name"`
+		}
+		json.Unmarshal(node, &order)
+		names = append(names, order.Name)
+	}
+	assert.Equal(t, []string{"#1001", "#1002", "#1003"}, names)
+	assert.Equal(t, map[string]interface{}{"query": "status:open"}, variables)
+}
+
+// Should stop with an error when a next page comes without a new end cursor
+func TestPaginateConnectionStuckCursor(t *testing.T) {
+	for _, cursor := range []string{`null`, `""`, `"cursor-1"`} {
+		requests := 0
+		mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			// an unchanged cursor is only noticed on the second page
+			w.Write([]byte(`{"data": {"orders": {"pageInfo": {"hasNextPage": true, "endCursor": ` + cursor + `},
+				"edges": [{"node": {"name": "#1001"}}]}}}`))
+		})
+
+		query := `query($after: String) { orders(first: 1, after: $after) { pageInfo { hasNextPage endCursor } edges { node { name } } } }`
+		nodes, errs := mock.PaginateConnection(query, nil, connectionAt("orders"))
+		server.Close()
+
+		assert.Equal(t, 1, len(errs), cursor)
+		assert.T(t, nodes == nil, cursor)
+		if cursor == `"cursor-1"` {
+			assert.Equal(t, 2, requests)
+		} else {
+			assert.Equal(t, 1, requests)
+		}
+	}
+}
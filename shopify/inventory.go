@@ -0,0 +1,343 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// inventoryItemIDsPerRequest is how many inventory items a single inventory_levels request can filter on
+const inventoryItemIDsPerRequest = 50
+
+const variantsBySKUQuery = `query($query: String!, $after: String) {
+  productVariants(first: 250, query: $query, after: $after) {
+    pageInfo { hasNextPage endCursor }
+    edges { node { sku inventoryItem { legacyResourceId } } }
+  }
+}`
+
+const inventoryTransfersQuery = `query($query: String!, $after: String) {
+  inventoryTransfers(first: 50, query: $query, after: $after) {
+    pageInfo { hasNextPage endCursor }
+    edges {
+      node {
+        id
+        name
+        status
+        dateCreated
+        origin { location { legacyResourceId } }
+        destination { location { legacyResourceId } }
+        lineItems(first: 250) {
+          edges { node { inventoryItem { legacyResourceId sku } totalQuantity shippedQuantity } }
+        }
+      }
+    }
+  }
+}`
+
+//InventoryTransfer is stock moved to a location, from another location or a supplier
+type InventoryTransfer struct {
+	ID   string
+	Name string
+	// Status is one of DRAFT, READY_TO_SHIP, IN_PROGRESS, TRANSFERRED or CANCELED
+	Status    string
+	CreatedAt ShopTime
+	// OriginLocationID is 0 when the stock doesn't come from a location of the store
+	OriginLocationID      int64
+	DestinationLocationID int64
+	LineItems             []InventoryTransferLineItem
+}
+
+//InventoryTransferLineItem is the quantity of an inventory item expected by a transfer
+type InventoryTransferLineItem struct {
+	InventoryItemID  int64
+	SKU              string
+	ExpectedQuantity int
+	ShippedQuantity  int
+}
+
+//GetInventoryItem returns an inventory item given its id
+func (shop *Shopify) GetInventoryItem(inventoryItemID int64) (*InventoryItem, []error) {
+	var inventoryItemResponse InventoryItemResponse
+	response, errors := shop.Get(fmt.Sprintf("inventory_items/%v", inventoryItemID))
+	if err := unmarshal(response, errors, &inventoryItemResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &inventoryItemResponse.InventoryItem, nil
+}
+
+//UpdateInventoryItem updates the given fields of an inventory item, e.g. {"cost": "25.00"} or {"tracked": false}.
+//Fields left out keep their value.
+func (shop *Shopify) UpdateInventoryItem(inventoryItemID int64, fields map[string]interface{}) (*InventoryItem, []error) {
+	var inventoryItemResponse InventoryItemResponse
+	body := map[string]interface{}{"id": inventoryItemID}
+	for key, value := range fields {
+		body[key] = value
+	}
+	response, errors := shop.Put(fmt.Sprintf("inventory_items/%v", inventoryItemID), map[string]interface{}{
+		"inventory_item": body,
+	})
+	if err := unmarshal(response, errors, &inventoryItemResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &inventoryItemResponse.InventoryItem, nil
+}
+
+//GetInventoryLevels returns the inventory levels matching parameters, e.g. inventory_item_ids or location_ids
+func (shop *Shopify) GetInventoryLevels(parameters map[string]string) ([]InventoryLevel, []error) {
+	if err := shop.checkLimit("inventory_levels", parameters); err != nil {
+		return nil, []error{err}
+	}
+	var inventoryLevels InventoryLevelsResponse
+	response, errors := shop.GetWithParameters("inventory_levels", parameters)
+	if err := unmarshal(response, errors, &inventoryLevels); len(err) > 0 {
+		return nil, err
+	}
+	return inventoryLevels.InventoryLevels, nil
+}
+
+// eachInventoryLevel pages the inventory levels of the given inventory items, as many at a time as
+// a single request can filter on, and calls fn for each of them
+func (shop *Shopify) eachInventoryLevel(itemIDs []string, fn func(InventoryLevel)) []error {
+	for start := 0; start < len(itemIDs); start += inventoryItemIDsPerRequest {
+		end := start + inventoryItemIDsPerRequest
+		if end > len(itemIDs) {
+			end = len(itemIDs)
+		}
+		parameters := map[string]string{
+			"inventory_item_ids": strings.Join(itemIDs[start:end], ","),
+			"limit":              strconv.Itoa(shop.maxLimit("inventory_levels")),
+		}
+		errs := shop.paginate("inventory_levels", parameters, func(page []byte) []error {
+			var inventoryLevels InventoryLevelsResponse
+			if err := unmarshal(page, nil, &inventoryLevels); len(err) > 0 {
+				return err
+			}
+			for _, level := range inventoryLevels.InventoryLevels {
+				fn(level)
+			}
+			return nil
+		})
+		if len(errs) > 0 {
+			return errs
+		}
+	}
+	return nil
+}
+
+//GetVariantAvailability returns the available quantity of a variant keyed by location id.
+//Variants whose inventory is not tracked by shopify have no levels and get an empty map.
+func (shop *Shopify) GetVariantAvailability(variantID int64) (map[int64]int, []error) {
+	variant, errs := shop.GetVariant(variantID)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	availability := make(map[int64]int)
+	if !variantTracksInventory(*variant) || variant.InventoryItemID == 0 {
+		return availability, nil
+	}
+
+	errs = shop.eachInventoryLevel([]string{strconv.FormatInt(variant.InventoryItemID, 10)}, func(level InventoryLevel) {
+		if level.Available != nil {
+			availability[level.LocationID] = *level.Available
+		}
+	})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return availability, nil
+}
+
+//IsVariantPurchasable tells whether a variant can be added to a cart: it has stock, its inventory policy
+//lets customers buy it when out of stock, or its inventory is not tracked by shopify
+func (shop *Shopify) IsVariantPurchasable(variantID int64) (bool, []error) {
+	variant, errs := shop.GetVariant(variantID)
+	if len(errs) > 0 {
+		return false, errs
+	}
+	if !variantTracksInventory(*variant) {
+		return true, nil
+	}
+	return variant.InventoryQuantity > 0 || variant.InventoryPolicy == "continue", nil
+}
+
+// variantTracksInventory tells whether shopify tracks the inventory of a variant, which is the only case
+// it keeps inventory levels and quantities for it
+func variantTracksInventory(variant Variant) bool {
+	return variant.InventoryManagement == "shopify"
+}
+
+//VariantInventory is a variant along with its available quantity at each location
+type VariantInventory struct {
+	Variant Variant
+	// Tracked tells whether shopify tracks the variant's inventory, untracked variants have no availability
+	Tracked bool
+	// Available is the quantity available keyed by location id
+	Available map[int64]int
+}
+
+//GetVariantsWithInventory returns the variants of a product joined with their inventory levels, which are
+//fetched for all the tracked variants at once
+func (shop *Shopify) GetVariantsWithInventory(productID int64) ([]VariantInventory, []error) {
+	var variants []Variant
+	endpoint := fmt.Sprintf("products/%v/variants", productID)
+	errs := shop.paginate(endpoint, map[string]string{"limit": strconv.Itoa(shop.maxLimit(endpoint))}, func(page []byte) []error {
+		var variantsResponse VariantsResponse
+		if err := unmarshal(page, nil, &variantsResponse); len(err) > 0 {
+			return err
+		}
+		variants = append(variants, variantsResponse.Variants...)
+		return nil
+	})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	inventories := make([]VariantInventory, len(variants))
+	byItem := make(map[int64][]int)
+	var itemIDs []string
+	for i, variant := range variants {
+		inventories[i] = VariantInventory{Variant: variant, Available: make(map[int64]int)}
+		if !variantTracksInventory(variant) || variant.InventoryItemID == 0 {
+			continue
+		}
+		inventories[i].Tracked = true
+		if _, ok := byItem[variant.InventoryItemID]; !ok {
+			itemIDs = append(itemIDs, strconv.FormatInt(variant.InventoryItemID, 10))
+		}
+		byItem[variant.InventoryItemID] = append(byItem[variant.InventoryItemID], i)
+	}
+	errs = shop.eachInventoryLevel(itemIDs, func(level InventoryLevel) {
+		if level.Available == nil {
+			return
+		}
+		for _, i := range byItem[level.InventoryItemID] {
+			inventories[i].Available[level.LocationID] = *level.Available
+		}
+	})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return inventories, nil
+}
+
+//GetTotalInventoryBySKU returns the quantity available across all locations of each SKU, adding up the
+//variants sharing a SKU. SKUs that match no variant are left out of the map.
+func (shop *Shopify) GetTotalInventoryBySKU(skus []string) (map[string]int, []error) {
+	itemSKUs, errs := shop.inventoryItemsBySKU(skus)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	totals := make(map[string]int)
+	var itemIDs []string
+	for itemID, sku := range itemSKUs {
+		// found SKUs are reported even when none is available
+		totals[sku] = 0
+		itemIDs = append(itemIDs, strconv.FormatInt(itemID, 10))
+	}
+	errs = shop.eachInventoryLevel(itemIDs, func(level InventoryLevel) {
+		if level.Available != nil {
+			totals[itemSKUs[level.InventoryItemID]] += *level.Available
+		}
+	})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return totals, nil
+}
+
+// inventoryItemsBySKU searches the variants with the given SKUs and returns the SKU of their inventory items
+func (shop *Shopify) inventoryItemsBySKU(skus []string) (map[int64]string, []error) {
+	wanted := make(map[string]bool)
+	var terms []string
+	for _, sku := range skus {
+		if sku == "" || wanted[sku] {
+			continue
+		}
+		wanted[sku] = true
+		terms = append(terms, "sku:"+strconv.Quote(sku))
+	}
+	itemSKUs := make(map[int64]string)
+	if len(terms) == 0 {
+		return itemSKUs, nil
+	}
+	variables := map[string]interface{}{"query": strings.Join(terms, " OR ")}
+	nodes, errs := shop.PaginateConnection(variantsBySKUQuery, variables, connectionAt("productVariants"))
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	for _, node := range nodes {
+		var variant struct {
+			SKU           string `json:"sku"`
+			InventoryItem struct {
+				LegacyResourceID int64 `json:"legacyResourceId,string"`
+			} `json:"inventoryItem"`
+		}
+		if err := json.Unmarshal(node, &variant); err != nil {
+			return nil, []error{err}
+		}
+		// the search is fuzzy, only keep the exact matches
+		if wanted[variant.SKU] {
+			itemSKUs[variant.InventoryItem.LegacyResourceID] = variant.SKU
+		}
+	}
+	return itemSKUs, nil
+}
+
+//GetInventoryTransfers returns the transfers of stock to a location, e.g. to tell the warehouse
+//what is incoming, whatever their status
+func (shop *Shopify) GetInventoryTransfers(locationID int64) ([]InventoryTransfer, []error) {
+	type location struct {
+		Location *struct {
+			LegacyResourceID int64 `json:"legacyResourceId,string"`
+		} `json:"location"`
+	}
+	variables := map[string]interface{}{"query": fmt.Sprintf("destination_id:%v", locationID)}
+	nodes, errs := shop.PaginateConnection(inventoryTransfersQuery, variables, connectionAt("inventoryTransfers"))
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	var transfers []InventoryTransfer
+	for _, raw := range nodes {
+		var node struct {
+			ID          string    `json:"id"`
+			Name        string    `json:"name"`
+			Status      string    `json:"status"`
+			DateCreated ShopTime  `json:"dateCreated"`
+			Origin      *location `json:"origin"`
+			Destination *location `json:"destination"`
+			LineItems   struct {
+				Edges []struct {
+					Node struct {
+						InventoryItem struct {
+							LegacyResourceID int64  `json:"legacyResourceId,string"`
+							SKU              string `json:"sku"`
+						} `json:"inventoryItem"`
+						TotalQuantity   int `json:"totalQuantity"`
+						ShippedQuantity int `json:"shippedQuantity"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"lineItems"`
+		}
+		if err := json.Unmarshal(raw, &node); err != nil {
+			return nil, []error{err}
+		}
+		transfer := InventoryTransfer{ID: node.ID, Name: node.Name, Status: node.Status, CreatedAt: node.DateCreated}
+		if node.Origin != nil && node.Origin.Location != nil {
+			transfer.OriginLocationID = node.Origin.Location.LegacyResourceID
+		}
+		if node.Destination != nil && node.Destination.Location != nil {
+			transfer.DestinationLocationID = node.Destination.Location.LegacyResourceID
+		}
+		for _, item := range node.LineItems.Edges {
+			transfer.LineItems = append(transfer.LineItems, InventoryTransferLineItem{
+				InventoryItemID:  item.Node.InventoryItem.LegacyResourceID,
+				SKU:              item.Node.InventoryItem.SKU,
+				ExpectedQuantity: item.Node.TotalQuantity,
+				ShippedQuantity:  item.Node.ShippedQuantity,
+			})
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers, nil
+}
@@ -0,0 +1,285 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should decode an inventory item with its cost
+func TestGetInventoryItem(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/inventory_items/808950810.json", "inventory_item.json"))
+	defer server.Close()
+
+	item, errs := mock.GetInventoryItem(808950810)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "IPOD2008PINK", item.SKU)
+	assert.Equal(t, "25.00", item.Cost)
+	assert.T(t, item.Tracked)
+	assert.T(t, item.CountryCodeOfOrigin == nil)
+}
+
+// Should PUT only the new cost of the inventory item, leaving its other fields untouched
+func TestUpdateInventoryItem(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/admin/inventory_items/808950810.json", r.URL.Path)
+
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, map[string]interface{}{"id": float64(808950810), "cost": "30.50"}, body["inventory_item"])
+		for _, field := range []string{"sku", "tracked", "harmonized_system_code"} {
+			_, sent := body["inventory_item"][field]
+			assert.T(t, !sent, field)
+		}
+
+		w.Write([]byte(`{"inventory_item": {"id": 808950810, "sku": "IPOD2008PINK", "cost": "30.50", "tracked": true}}`))
+	})
+	defer server.Close()
+
+	item, errs := mock.UpdateInventoryItem(808950810, map[string]interface{}{"cost": "30.50"})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "30.50", item.Cost)
+}
+
+// Should chain the variant and inventory levels lookups into a location map
+func TestGetVariantAvailability(t *testing.T) {
+	handler := routesHandler(t, map[string]string{
+		"/admin/variants/808950810.json": "variant.json",
+		"/admin/inventory_levels.json":   "inventory_levels.json",
+	})
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/inventory_levels.json" {
+			assert.Equal(t, "808950810", r.URL.Query().Get("inventory_item_ids"))
+		}
+		handler(w, r)
+	})
+	defer server.Close()
+
+	availability, errs := mock.GetVariantAvailability(808950810)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, map[int64]int{487838322: 9, 905684977: 1}, availability)
+}
+
+// Should follow the pages of the inventory levels of a variant stocked at many locations
+func TestGetVariantAvailabilityPaged(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/variants/808950810.json":
+			w.Write(loadFixture(t, "variant.json"))
+		case "/admin/inventory_levels.json":
+			query := r.URL.Query()
+			assert.Equal(t, "250", query.Get("limit"))
+			if query.Get("page_info") == "" {
+				assert.Equal(t, "808950810", query.Get("inventory_item_ids"))
+				w.Header().Set("Link", `<https://mock.myshopify.com/admin/inventory_levels.json?page_info=bGV2&limit=250>; rel="next"`)
+				w.Write([]byte(`{"inventory_levels": [{"inventory_item_id": 808950810, "location_id": 487838322, "available": 9}]}`))
+				return
+			}
+			assert.Equal(t, "bGV2", query.Get("page_info"))
+			w.Write([]byte(`{"inventory_levels": [{"inventory_item_id": 808950810, "location_id": 905684977, "available": 1}]}`))
+		default:
+			t.Errorf("unexpected request to %v", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+	defer server.Close()
+
+	availability, errs := mock.GetVariantAvailability(808950810)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, map[int64]int{487838322: 9, 905684977: 1}, availability)
+}
+
+// Should not look for inventory levels of an untracked variant
+func TestGetVariantAvailabilityUntracked(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/variants/49148385.json", "variant_untracked.json"))
+	defer server.Close()
+
+	availability, errs := mock.GetVariantAvailability(49148385)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 0, len(availability))
+}
+
+// Should tell a variant is purchasable when in stock, oversold or untracked, and not when out of stock
+func TestIsVariantPurchasable(t *testing.T) {
+	variants := map[string]string{
+		"/admin/variants/1.json": `{"variant": {"id": 1, "inventory_management": "shopify", "inventory_policy": "deny", "inventory_quantity": 3}}`,
+		"/admin/variants/2.json": `{"variant": {"id": 2, "inventory_management": "shopify", "inventory_policy": "continue", "inventory_quantity": 0}}`,
+		"/admin/variants/3.json": `{"variant": {"id": 3, "inventory_management": null, "inventory_policy": "deny", "inventory_quantity": 0}}`,
+		"/admin/variants/4.json": `{"variant": {"id": 4, "inventory_management": "shopify", "inventory_policy": "deny", "inventory_quantity": -2}}`,
+		"/admin/variants/5.json": `{"variant": {"id": 5, "inventory_management": "amazon_marketplace_web", "inventory_policy": "deny", "inventory_quantity": 0}}`,
+	}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		variant, ok := variants[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request to %v", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(variant))
+	})
+	defer server.Close()
+
+	for id, expected := range map[int64]bool{1: true, 2: true, 3: true, 4: false, 5: true} {
+		purchasable, errs := mock.IsVariantPurchasable(id)
+		assert.T(t, errs == nil, errs)
+		assert.Equal(t, expected, purchasable, id)
+	}
+}
+
+// Should fetch the levels of the tracked variants in a single call and join them per location
+func TestGetVariantsWithInventory(t *testing.T) {
+	levelCalls := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/products/632910392/variants.json":
+			w.Write([]byte(`{"variants": [
+				{"id": 808950810, "sku": "IPOD2008PINK", "inventory_item_id": 808950810, "inventory_management": "shopify"},
+				{"id": 49148385, "sku": "IPOD2008RED", "inventory_item_id": 49148385, "inventory_management": "shopify"},
+				{"id": 39072856, "sku": "IPOD2008GREEN", "inventory_item_id": 39072856, "inventory_management": null}
+			]}`))
+		case "/admin/inventory_levels.json":
+			levelCalls++
+			assert.Equal(t, "808950810,49148385", r.URL.Query().Get("inventory_item_ids"))
+			w.Write([]byte(`{"inventory_levels": [
+				{"inventory_item_id": 808950810, "location_id": 487838322, "available": 9},
+				{"inventory_item_id": 808950810, "location_id": 905684977, "available": 1},
+				{"inventory_item_id": 49148385, "location_id": 487838322, "available": null}
+			]}`))
+		default:
+			t.Errorf("unexpected request to %v", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	inventories, errs := mock.GetVariantsWithInventory(632910392)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 1, levelCalls)
+	assert.Equal(t, 3, len(inventories))
+	assert.Equal(t, "IPOD2008PINK", inventories[0].Variant.SKU)
+	assert.T(t, inventories[0].Tracked)
+	assert.Equal(t, map[int64]int{487838322: 9, 905684977: 1}, inventories[0].Available)
+	assert.T(t, inventories[1].Tracked)
+	assert.Equal(t, map[int64]int{}, inventories[1].Available)
+	assert.T(t, !inventories[2].Tracked)
+	assert.Equal(t, map[int64]int{}, inventories[2].Available)
+}
+
+// Should follow the next links of both the variants and the inventory levels
+func TestGetVariantsWithInventoryPaged(t *testing.T) {
+	levelCalls := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/admin/products/632910392/variants.json":
+			if query.Get("page_info") == "" {
+				w.Header().Set("Link", `<https://mock.myshopify.com/admin/products/632910392/variants.json?page_info=dmFy&limit=250>; rel="next"`)
+				w.Write([]byte(`{"variants": [{"id": 808950810, "inventory_item_id": 808950810, "inventory_management": "shopify"}]}`))
+				return
+			}
+			assert.Equal(t, "dmFy", query.Get("page_info"))
+			w.Write([]byte(`{"variants": [{"id": 49148385, "inventory_item_id": 49148385, "inventory_management": "shopify"}]}`))
+		case "/admin/inventory_levels.json":
+			levelCalls++
+			if query.Get("page_info") == "" {
+				assert.Equal(t, "808950810,49148385", query.Get("inventory_item_ids"))
+				assert.Equal(t, "250", query.Get("limit"))
+				w.Header().Set("Link", `<https://mock.myshopify.com/admin/inventory_levels.json?page_info=bGV2&limit=250>; rel="next"`)
+				w.Write([]byte(`{"inventory_levels": [{"inventory_item_id": 808950810, "location_id": 487838322, "available": 9}]}`))
+				return
+			}
+			assert.Equal(t, "bGV2", query.Get("page_info"))
+			w.Write([]byte(`{"inventory_levels": [
+				{"inventory_item_id": 808950810, "location_id": 905684977, "available": 1},
+				{"inventory_item_id": 49148385, "location_id": 487838322, "available": 4}
+			]}`))
+		default:
+			t.Errorf("unexpected request to %v", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	inventories, errs := mock.GetVariantsWithInventory(632910392)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, levelCalls)
+	assert.Equal(t, 2, len(inventories))
+	assert.Equal(t, map[int64]int{487838322: 9, 905684977: 1}, inventories[0].Available)
+	assert.Equal(t, map[int64]int{487838322: 4}, inventories[1].Available)
+}
+
+// Should sum the availability of every variant of each SKU across locations and pages of levels
+func TestGetTotalInventoryBySKU(t *testing.T) {
+	variants := loadFixture(t, "graphql_variants_by_sku.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/api/graphql.json":
+			var body map[string]map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, `sku:"IPOD2008PINK" OR sku:"IPOD2008RED" OR sku:"MISSING"`, body["variables"]["query"])
+			w.Write(variants)
+		case "/admin/inventory_levels.json":
+			if r.URL.Query().Get("page_info") == "bGV2" {
+				w.Write([]byte(`{"inventory_levels": [{"inventory_item_id": 808950811, "location_id": 905684977, "available": 2}]}`))
+				return
+			}
+			ids := strings.Split(r.URL.Query().Get("inventory_item_ids"), ",")
+			sort.Strings(ids)
+			assert.Equal(t, []string{"49148385", "808950810", "808950811"}, ids)
+			assert.Equal(t, "250", r.URL.Query().Get("limit"))
+			w.Header().Set("Link", `<https://mock.myshopify.com/admin/inventory_levels.json?page_info=bGV2&limit=250>; rel="next"`)
+			w.Write([]byte(`{"inventory_levels": [
+				{"inventory_item_id": 808950810, "location_id": 487838322, "available": 9},
+				{"inventory_item_id": 808950810, "location_id": 905684977, "available": 1},
+				{"inventory_item_id": 808950811, "location_id": 487838322, "available": 5},
+				{"inventory_item_id": 49148385, "location_id": 487838322, "available": 0},
+				{"inventory_item_id": 49148385, "location_id": 905684977, "available": null}
+			]}`))
+		default:
+			t.Errorf("unexpected request to %v", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	totals, errs := mock.GetTotalInventoryBySKU([]string{"IPOD2008PINK", "IPOD2008RED", "IPOD2008PINK", "MISSING"})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, map[string]int{"IPOD2008PINK": 17, "IPOD2008RED": 0}, totals)
+}
+
+// Should decode the transfers to the location along with their expected quantities
+func TestGetInventoryTransfers(t *testing.T) {
+	fixture := fixtureHandler(t, "/admin/api/graphql.json", "graphql_inventory_transfers.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "destination_id:487838322", body["variables"]["query"])
+		fixture(w, r)
+	})
+	defer server.Close()
+
+	transfers, errs := mock.GetInventoryTransfers(487838322)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(transfers))
+	assert.Equal(t, "IN_PROGRESS", transfers[0].Status)
+	assert.Equal(t, int64(905684977), transfers[0].OriginLocationID)
+	assert.Equal(t, int64(487838322), transfers[0].DestinationLocationID)
+	assert.Equal(t, []InventoryTransferLineItem{
+		{InventoryItemID: 808950810, SKU: "IPOD2008PINK", ExpectedQuantity: 20, ShippedQuantity: 20},
+		{InventoryItemID: 49148385, SKU: "IPOD2008RED", ExpectedQuantity: 5, ShippedQuantity: 0},
+	}, transfers[0].LineItems)
+	assert.Equal(t, "DRAFT", transfers[1].Status)
+	assert.Equal(t, int64(0), transfers[1].OriginLocationID)
+	assert.Equal(t, 12, transfers[1].LineItems[0].ExpectedQuantity)
+}
@@ -0,0 +1,43 @@
+package shopify
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxLimit is the largest page size most shopify list endpoints accept
+const defaultMaxLimit = 250
+
+// WithMaxLimit Overrides the largest limit accepted by a list endpoint, keyed by its last
+// path segment, e.g. WithMaxLimit("metafields", 50) covers every resource's metafields.
+func WithMaxLimit(endpoint string, max int) Option {
+	return func(shopify *Shopify) {
+		if shopify.maxLimits == nil {
+			shopify.maxLimits = make(map[string]int)
+		}
+		shopify.maxLimits[endpoint] = max
+	}
+}
+
+// checkLimit rejects a limit parameter shopify would refuse for endpoint before sending the request
+func (shopify *Shopify) checkLimit(endpoint string, parameters map[string]string) error {
+	value, ok := parameters["limit"]
+	if !ok {
+		return nil
+	}
+	max := shopify.maxLimit(endpoint)
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > max {
+		return fmt.Errorf("invalid limit %q for %v, it must be between 1 and %v", value, endpoint, max)
+	}
+	return nil
+}
+
+func (shopify *Shopify) maxLimit(endpoint string) int {
+	resource := endpoint[strings.LastIndex(endpoint, "/")+1:]
+	if max, ok := shopify.maxLimits[resource]; ok {
+		return max
+	}
+	return defaultMaxLimit
+}
@@ -0,0 +1,35 @@
+package shopify
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should reject a limit above the endpoint's max without calling shopify
+func TestLimitAboveMaxRejected(t *testing.T) {
+	calls := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+	defer server.Close()
+
+	products, errs := mock.GetProductsWithParameters(map[string]string{"limit": "500"})
+
+	assert.T(t, products == nil)
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, `invalid limit "500" for products, it must be between 1 and 250`, errs[0].Error())
+	assert.Equal(t, 0, calls)
+}
+
+func TestCheckLimit(t *testing.T) {
+	client := New("mystore", "key", "pass", WithMaxLimit("metafields", 50))
+
+	assert.T(t, client.checkLimit("products", nil) == nil)
+	assert.T(t, client.checkLimit("products", map[string]string{"limit": "250"}) == nil)
+	assert.T(t, client.checkLimit("products", map[string]string{"limit": "0"}) != nil)
+	assert.T(t, client.checkLimit("products", map[string]string{"limit": "many"}) != nil)
+	assert.T(t, client.checkLimit("orders/1/metafields", map[string]string{"limit": "50"}) == nil)
+	assert.T(t, client.checkLimit("orders/1/metafields", map[string]string{"limit": "51"}) != nil)
+}
@@ -0,0 +1,71 @@
+package shopify
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// Shopify's REST leaky bucket holds 40 requests and leaks 2 per second
+	bucketSize = 40
+	leakRate   = 2
+	// Header reporting the bucket usage, e.g. "32/40"
+	callLimitHeader = "X-Shopify-Shop-Api-Call-Limit"
+)
+
+// limiter mirrors shopify's leaky bucket on the client side so that bursts
+// go through untouched and sustained load is paced at the leak rate.
+type limiter struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64
+	level    float64
+	last     time.Time
+	clock    clock
+}
+
+func newLimiter(capacity, rate float64, c clock) *limiter {
+	return &limiter{capacity: capacity, rate: rate, last: c.Now(), clock: c}
+}
+
+// wait blocks until there is room in the bucket for one more request
+func (l *limiter) wait() {
+	l.mu.Lock()
+	l.leak()
+	var delay time.Duration
+	if l.level+1 > l.capacity {
+		delay = time.Duration((l.level + 1 - l.capacity) / l.rate * float64(time.Second))
+	}
+	l.level++
+	l.mu.Unlock()
+
+	if delay > 0 {
+		l.clock.Sleep(delay)
+	}
+}
+
+// observe syncs the bucket with the usage reported by shopify
+func (l *limiter) observe(callLimit string) {
+	var used, capacity float64
+	if _, err := fmt.Sscanf(callLimit, "%g/%g", &used, &capacity); err != nil || capacity <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.leak()
+	l.capacity = capacity
+	if used > l.level {
+		l.level = used
+	}
+}
+
+// leak drains the bucket for the time elapsed since the last call, must hold mu
+func (l *limiter) leak() {
+	now := l.clock.Now()
+	l.level -= now.Sub(l.last).Seconds() * l.rate
+	if l.level < 0 {
+		l.level = 0
+	}
+	l.last = now
+}
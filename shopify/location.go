@@ -0,0 +1,57 @@
+package shopify
+
+//GetLocations returns all the locations of the store
+func (shop *Shopify) GetLocations() ([]Location, []error) {
+	var locations LocationsResponse
+	response, errors := shop.Get("locations")
+	if err := unmarshal(response, errors, &locations); len(err) > 0 {
+		return nil, err
+	}
+	return locations.Locations, nil
+}
+
+//GetFulfillableLocations returns the locations orders can be fulfilled from: the active ones of the store and
+//the active legacy ones still linked to a fulfillment service, which fulfills their orders itself. Those get the
+//handle of their service in FulfillmentService, while a legacy location whose service is gone, e.g. after its
+//app was uninstalled, cannot fulfill and is left out.
+func (shop *Shopify) GetFulfillableLocations() ([]Location, []error) {
+	locations, errs := shop.GetLocations()
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	var services map[int64]string
+	fulfillable := []Location{}
+	for _, location := range locations {
+		if !location.Active {
+			continue
+		}
+		if location.Legacy {
+			if services == nil {
+				if services, errs = shop.fulfillmentServicesByLocation(); len(errs) > 0 {
+					return nil, errs
+				}
+			}
+			handle, linked := services[location.ID]
+			if !linked {
+				continue
+			}
+			location.FulfillmentService = handle
+		}
+		fulfillable = append(fulfillable, location)
+	}
+	return fulfillable, nil
+}
+
+// fulfillmentServicesByLocation returns the handle of every fulfillment service of the store keyed by its location id
+func (shop *Shopify) fulfillmentServicesByLocation() (map[int64]string, []error) {
+	var servicesResponse FulfillmentServicesResponse
+	response, errors := shop.GetWithParameters("fulfillment_services", map[string]string{"scope": "all"})
+	if err := unmarshal(response, errors, &servicesResponse); len(err) > 0 {
+		return nil, err
+	}
+	services := make(map[int64]string)
+	for _, service := range servicesResponse.FulfillmentServices {
+		services[service.LocationID] = service.Handle
+	}
+	return services, nil
+}
@@ -0,0 +1,52 @@
+package shopify
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should only keep the active locations, including the fulfillment service ones still linked to their service
+func TestGetFulfillableLocations(t *testing.T) {
+	handler := routesHandler(t, map[string]string{
+		"/admin/locations.json":            "locations.json",
+		"/admin/fulfillment_services.json": "fulfillment_services.json",
+	})
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/fulfillment_services.json" {
+			assert.Equal(t, "all", r.URL.Query().Get("scope"))
+		}
+		handler(w, r)
+	})
+	defer server.Close()
+
+	locations, errs := mock.GetFulfillableLocations()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(locations))
+	assert.Equal(t, int64(487838322), locations[0].ID)
+	assert.T(t, !locations[0].Legacy)
+	assert.Equal(t, "", locations[0].FulfillmentService)
+	assert.Equal(t, "Shipwire", locations[1].Name)
+	assert.T(t, locations[1].Legacy)
+	assert.Equal(t, "shipwire", locations[1].FulfillmentService)
+}
+
+// Should not look for fulfillment services when no active location belongs to one
+func TestGetFulfillableLocationsWithoutServices(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/locations.json", r.URL.Path)
+		w.Write([]byte(`{"locations": [
+			{"id": 487838322, "name": "Fifth Avenue AppleStore", "active": true, "legacy": false},
+			{"id": 1072404542, "name": "Shipwire", "active": false, "legacy": true}
+		]}`))
+	})
+	defer server.Close()
+
+	locations, errs := mock.GetFulfillableLocations()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 1, len(locations))
+	assert.Equal(t, int64(487838322), locations[0].ID)
+}
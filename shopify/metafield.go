@@ -0,0 +1,234 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//MetafieldResult is the outcome of writing one of the metafields given to SetMetafields
+type MetafieldResult struct {
+	// Index of the metafield in the input slice
+	Index int
+	// Metafield as created or updated by shopify, nil on failure
+	Metafield *Metafield
+	// Error returned by shopify, e.g. a 422 for a value that doesn't match the type, nil on success
+	Error *ShopifyError
+}
+
+//GetMetafields returns the metafields of a resource, e.g. GetMetafields("products", productID, nil).
+//An empty resource lists the shop's own metafields.
+func (shop *Shopify) GetMetafields(resource string, ownerID int64, parameters map[string]string) ([]Metafield, []error) {
+	endpoint := metafieldsEndpoint(resource, ownerID)
+	if err := shop.checkLimit(endpoint, parameters); err != nil {
+		return nil, []error{err}
+	}
+	var metafields MetafieldsResponse
+	response, errors := shop.GetWithParameters(endpoint, parameters)
+	if err := unmarshal(response, errors, &metafields); len(err) > 0 {
+		return nil, err
+	}
+	return metafields.Metafields, nil
+}
+
+//CreateMetafield creates a metafield on a resource
+func (shop *Shopify) CreateMetafield(resource string, ownerID int64, metafield Metafield) (*Metafield, []error) {
+	var metafieldResponse MetafieldResponse
+	response, errors := shop.Post(metafieldsEndpoint(resource, ownerID), map[string]interface{}{"metafield": metafield})
+	if err := unmarshal(response, errors, &metafieldResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &metafieldResponse.Metafield, nil
+}
+
+//UpdateMetafield updates the value and type of an existing metafield of a resource
+func (shop *Shopify) UpdateMetafield(resource string, ownerID int64, metafield Metafield) (*Metafield, []error) {
+	var metafieldResponse MetafieldResponse
+	endpoint := fmt.Sprintf("%v/%v", metafieldsEndpoint(resource, ownerID), metafield.ID)
+	response, errors := shop.Put(endpoint, map[string]interface{}{"metafield": map[string]interface{}{
+		"id":    metafield.ID,
+		"value": metafield.Value,
+		"type":  metafield.Type,
+	}})
+	if err := unmarshal(response, errors, &metafieldResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &metafieldResponse.Metafield, nil
+}
+
+//SetMetafield updates the resource's metafield with the same namespace and key, or creates it if there is none
+func (shop *Shopify) SetMetafield(resource string, ownerID int64, metafield Metafield) (*Metafield, []error) {
+	existing, errs := shop.GetMetafields(resource, ownerID, map[string]string{
+		"namespace": metafield.Namespace,
+		"key":       metafield.Key,
+	})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	for _, current := range existing {
+		if current.Namespace == metafield.Namespace && current.Key == metafield.Key {
+			metafield.ID = current.ID
+			return shop.UpdateMetafield(resource, ownerID, metafield)
+		}
+	}
+	metafield.ID = 0
+	return shop.CreateMetafield(resource, ownerID, metafield)
+}
+
+//SetMetafields creates or updates each metafield on the resource given by its OwnerResource and OwnerID,
+//e.g. "product", an empty owner resource meaning the shop. The existing metafields of every owner are paged
+//through once to find the ones with the same namespace and key, and the writes are paced by the rate limiter.
+//A metafield rejected by shopify does not stop the batch and is reported in its MetafieldResult instead.
+func (shop *Shopify) SetMetafields(metafields []Metafield) ([]MetafieldResult, []error) {
+	var errs []error
+	results := make([]MetafieldResult, len(metafields))
+	existing := make(map[string][]Metafield)
+	for i, metafield := range metafields {
+		results[i].Index = i
+		resource := ownerResourceEndpoint(metafield.OwnerResource)
+		endpoint := metafieldsEndpoint(resource, metafield.OwnerID)
+		current, listed := existing[endpoint]
+		if !listed {
+			var errors []error
+			current, errors = shop.allMetafields(endpoint)
+			if shopifyError := findShopifyError(errors); shopifyError != nil {
+				results[i].Error = shopifyError
+				continue
+			}
+			if len(errors) > 0 {
+				errs = append(errs, errors...)
+				continue
+			}
+			existing[endpoint] = current
+		}
+
+		metafield.ID = 0
+		for _, other := range current {
+			if other.Namespace == metafield.Namespace && other.Key == metafield.Key {
+				metafield.ID = other.ID
+				break
+			}
+		}
+		var written *Metafield
+		var errors []error
+		if metafield.ID != 0 {
+			written, errors = shop.UpdateMetafield(resource, metafield.OwnerID, metafield)
+		} else {
+			written, errors = shop.CreateMetafield(resource, metafield.OwnerID, metafield)
+		}
+		if shopifyError := findShopifyError(errors); shopifyError != nil {
+			results[i].Error = shopifyError
+			continue
+		}
+		if len(errors) > 0 {
+			errs = append(errs, errors...)
+			continue
+		}
+		if metafield.ID == 0 {
+			// a later metafield of the batch with the same namespace and key updates this one
+			existing[endpoint] = append(current, Metafield{ID: written.ID, Namespace: metafield.Namespace, Key: metafield.Key})
+		}
+		results[i].Metafield = written
+	}
+	return results, errs
+}
+
+// allMetafields pages through every metafield of the endpoint
+func (shop *Shopify) allMetafields(endpoint string) ([]Metafield, []error) {
+	var metafields []Metafield
+	errs := shop.paginate(endpoint, map[string]string{"limit": strconv.Itoa(shop.maxLimit(endpoint))}, func(page []byte) []error {
+		var metafieldsResponse MetafieldsResponse
+		if err := unmarshal(page, nil, &metafieldsResponse); len(err) > 0 {
+			return err
+		}
+		metafields = append(metafields, metafieldsResponse.Metafields...)
+		return nil
+	})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return metafields, nil
+}
+
+//GetOrderMetafields returns the metafields of an order
+func (shop *Shopify) GetOrderMetafields(orderID int64) ([]Metafield, []error) {
+	return shop.GetMetafields("orders", orderID, nil)
+}
+
+//SetOrderMetafield creates or updates the order's metafield with the given namespace and key
+func (shop *Shopify) SetOrderMetafield(orderID int64, namespace, key, value, mtype string) (*Metafield, []error) {
+	return shop.SetMetafield("orders", orderID, Metafield{Namespace: namespace, Key: key, Value: value, Type: mtype})
+}
+
+//GetShopMetafield returns the shop's metafield with the given namespace and key, nil when there is none
+func (shop *Shopify) GetShopMetafield(namespace, key string) (*Metafield, []error) {
+	metafields, errs := shop.GetMetafields("", 0, map[string]string{"namespace": namespace, "key": key})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	for _, metafield := range metafields {
+		if metafield.Namespace == namespace && metafield.Key == key {
+			return &metafield, nil
+		}
+	}
+	return nil, nil
+}
+
+//SetShopMetafield creates or updates the shop's metafield with the given namespace and key,
+//e.g. the settings of a checkout customization
+func (shop *Shopify) SetShopMetafield(namespace, key, value, mtype string) (*Metafield, []error) {
+	return shop.SetMetafield("", 0, Metafield{Namespace: namespace, Key: key, Value: value, Type: mtype})
+}
+
+const metafieldDefinitionsQuery = `query($ownerType: MetafieldOwnerType!, $after: String) {
+  metafieldDefinitions(first: 250, ownerType: $ownerType, after: $after) {
+    pageInfo { hasNextPage endCursor }
+    edges { node { id name namespace key description type { name } validations { name value } } }
+  }
+}`
+
+//GetMetafieldDefinitions returns the metafield definitions of an owner type, e.g. PRODUCT or ORDER
+func (shop *Shopify) GetMetafieldDefinitions(ownerType string) ([]MetafieldDefinition, []error) {
+	variables := map[string]interface{}{"ownerType": ownerType}
+	nodes, errs := shop.PaginateConnection(metafieldDefinitionsQuery, variables, connectionAt("metafieldDefinitions"))
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	var definitions []MetafieldDefinition
+	for _, node := range nodes {
+		var decoded struct {
+			MetafieldDefinition
+			Type struct {
+				Name string `json:"name"`
+			} `json:"type"`
+		}
+		if err := json.Unmarshal(node, &decoded); err != nil {
+			return nil, []error{err}
+		}
+		definition := decoded.MetafieldDefinition
+		definition.Type = decoded.Type.Name
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+// metafieldsEndpoint returns the metafields endpoint of a resource, or the shop's one when resource is empty
+func metafieldsEndpoint(resource string, ownerID int64) string {
+	if resource == "" {
+		return "metafields"
+	}
+	return fmt.Sprintf("%v/%v/metafields", resource, ownerID)
+}
+
+// ownerResourceEndpoint returns the endpoint of a metafield's owner resource, e.g. products for product,
+// or an empty one for the shop
+func ownerResourceEndpoint(ownerResource string) string {
+	if ownerResource == "" || ownerResource == "shop" {
+		return ""
+	}
+	if strings.HasSuffix(ownerResource, "s") {
+		return ownerResource
+	}
+	return ownerResource + "s"
+}
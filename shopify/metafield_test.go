@@ -0,0 +1,248 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// metafieldsHandler lists the given fixture (or an empty list) and records the created or updated metafield
+func metafieldsHandler(t *testing.T, endpoint, fixture string, methods *[]string, written *map[string]interface{}) http.HandlerFunc {
+	listing := []byte(`{"metafields":[]}`)
+	if fixture != "" {
+		listing = loadFixture(t, fixture)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		*methods = append(*methods, r.Method+" "+r.URL.Path)
+		if r.Method == "GET" {
+			assert.Equal(t, "/admin/"+endpoint+".json", r.URL.Path)
+			w.Write(listing)
+			return
+		}
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		*written = body["metafield"]
+		json.NewEncoder(w).Encode(map[string]interface{}{"metafield": map[string]interface{}{
+			"id":    915396079,
+			"value": body["metafield"]["value"],
+		}})
+	}
+}
+
+// Should create the order metafield when none has the namespace and key
+func TestSetOrderMetafieldCreate(t *testing.T) {
+	var methods []string
+	var written map[string]interface{}
+	mock, server := newMockShopify(t, metafieldsHandler(t, "orders/450789469/metafields", "", &methods, &written))
+	defer server.Close()
+
+	metafield, errs := mock.SetOrderMetafield(450789469, "fulfillment", "warehouse", "toronto", "single_line_text_field")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"GET /admin/orders/450789469/metafields.json", "POST /admin/orders/450789469/metafields.json"}, methods)
+	assert.Equal(t, "fulfillment", written["namespace"])
+	assert.Equal(t, "warehouse", written["key"])
+	assert.Equal(t, "toronto", written["value"])
+	assert.Equal(t, "single_line_text_field", written["type"])
+	assert.Equal(t, int64(915396079), metafield.ID)
+}
+
+// Should update the existing order metafield with the same namespace and key
+func TestSetOrderMetafieldUpdate(t *testing.T) {
+	var methods []string
+	var written map[string]interface{}
+	mock, server := newMockShopify(t, metafieldsHandler(t, "orders/450789469/metafields", "order_metafields.json", &methods, &written))
+	defer server.Close()
+
+	metafield, errs := mock.SetOrderMetafield(450789469, "fulfillment", "warehouse", "ottawa", "single_line_text_field")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"GET /admin/orders/450789469/metafields.json", "PUT /admin/orders/450789469/metafields/915396079.json"}, methods)
+	assert.Equal(t, float64(915396079), written["id"])
+	assert.Equal(t, "ottawa", written["value"])
+	assert.Equal(t, "ottawa", metafield.Value)
+}
+
+// Should find the shop metafield by namespace and key
+func TestGetShopMetafield(t *testing.T) {
+	fixture := fixtureHandler(t, "/admin/metafields.json", "shop_metafields.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "checkout", r.URL.Query().Get("namespace"))
+		fixture(w, r)
+	})
+	defer server.Close()
+
+	metafield, errs := mock.GetShopMetafield("checkout", "gift_message")
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "enabled", metafield.Value)
+
+	metafield, errs = mock.GetShopMetafield("checkout", "missing")
+	assert.T(t, errs == nil, errs)
+	assert.T(t, metafield == nil)
+}
+
+// Should create the shop metafield when none has the namespace and key
+func TestSetShopMetafieldCreate(t *testing.T) {
+	var methods []string
+	var written map[string]interface{}
+	mock, server := newMockShopify(t, metafieldsHandler(t, "metafields", "", &methods, &written))
+	defer server.Close()
+
+	_, errs := mock.SetShopMetafield("checkout", "gift_message", "enabled", "single_line_text_field")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"GET /admin/metafields.json", "POST /admin/metafields.json"}, methods)
+	assert.Equal(t, "checkout", written["namespace"])
+	assert.Equal(t, "enabled", written["value"])
+}
+
+// Should update the existing shop metafield with the same namespace and key
+func TestSetShopMetafieldUpdate(t *testing.T) {
+	var methods []string
+	var written map[string]interface{}
+	mock, server := newMockShopify(t, metafieldsHandler(t, "metafields", "shop_metafields.json", &methods, &written))
+	defer server.Close()
+
+	_, errs := mock.SetShopMetafield("checkout", "gift_message", "disabled", "single_line_text_field")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"GET /admin/metafields.json", "PUT /admin/metafields/721389482.json"}, methods)
+	assert.Equal(t, float64(721389482), written["id"])
+	assert.Equal(t, "disabled", written["value"])
+}
+
+// Should list each owner's metafields once, update the existing ones, create the others and report the rejected ones
+func TestSetMetafields(t *testing.T) {
+	var methods []string
+	created := int64(1001)
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/admin/orders/450789469/metafields.json":
+			assert.Equal(t, "250", r.URL.Query().Get("limit"))
+			w.Write(loadFixture(t, "order_metafields.json"))
+		case r.Method == "GET":
+			w.Write([]byte(`{"metafields":[]}`))
+		default:
+			var body map[string]map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["metafield"]["value"] == "" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(`{"errors": {"value": ["can't be blank"]}}`))
+				return
+			}
+			id, _ := body["metafield"]["id"].(float64)
+			if id == 0 {
+				id = float64(created)
+				created++
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"metafield": map[string]interface{}{
+				"id":    id,
+				"key":   body["metafield"]["key"],
+				"value": body["metafield"]["value"],
+			}})
+		}
+	})
+	defer server.Close()
+
+	results, errs := mock.SetMetafields([]Metafield{
+		{OwnerResource: "order", OwnerID: 450789469, Namespace: "fulfillment", Key: "warehouse", Value: "ottawa", Type: "single_line_text_field"},
+		{OwnerResource: "product", OwnerID: 632910392, Namespace: "specs", Key: "material", Value: "aluminium", Type: "single_line_text_field"},
+		{OwnerResource: "product", OwnerID: 632910392, Namespace: "specs", Key: "weight", Value: "", Type: "single_line_text_field"},
+		{OwnerResource: "product", OwnerID: 632910392, Namespace: "specs", Key: "material", Value: "steel", Type: "single_line_text_field"},
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{
+		"GET /admin/orders/450789469/metafields.json",
+		"PUT /admin/orders/450789469/metafields/915396079.json",
+		"GET /admin/products/632910392/metafields.json",
+		"POST /admin/products/632910392/metafields.json",
+		"POST /admin/products/632910392/metafields.json",
+		"PUT /admin/products/632910392/metafields/1001.json",
+	}, methods)
+	assert.Equal(t, 4, len(results))
+	assert.Equal(t, int64(915396079), results[0].Metafield.ID)
+	assert.Equal(t, "ottawa", results[0].Metafield.Value)
+	assert.Equal(t, int64(1001), results[1].Metafield.ID)
+	assert.T(t, results[2].Metafield == nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, results[2].Error.StatusCode)
+	assert.Equal(t, ErrorMessages{"value": {"can't be blank"}}, results[2].Error.Errors)
+	assert.Equal(t, 3, results[3].Index)
+	assert.Equal(t, "steel", results[3].Metafield.Value)
+}
+
+// Should update a metafield found on a later page of the owner's metafields instead of creating a duplicate
+func TestSetMetafieldsPaged(t *testing.T) {
+	var methods []string
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "GET" && r.URL.Query().Get("page_info") == "":
+			w.Header().Set("Link", `<https://mock.myshopify.com/admin/products/632910392/metafields.json?page_info=bWV0&limit=250>; rel="next"`)
+			w.Write([]byte(`{"metafields": [{"id": 1, "namespace": "specs", "key": "color", "value": "pink"}]}`))
+		case r.Method == "GET":
+			assert.Equal(t, "bWV0", r.URL.Query().Get("page_info"))
+			w.Write([]byte(`{"metafields": [{"id": 2, "namespace": "specs", "key": "material", "value": "aluminium"}]}`))
+		default:
+			w.Write([]byte(`{"metafield": {"id": 2, "namespace": "specs", "key": "material", "value": "steel"}}`))
+		}
+	})
+	defer server.Close()
+
+	results, errs := mock.SetMetafields([]Metafield{
+		{OwnerResource: "product", OwnerID: 632910392, Namespace: "specs", Key: "material", Value: "steel", Type: "single_line_text_field"},
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{
+		"GET /admin/products/632910392/metafields.json",
+		"GET /admin/products/632910392/metafields.json",
+		"PUT /admin/products/632910392/metafields/2.json",
+	}, methods)
+	assert.Equal(t, int64(2), results[0].Metafield.ID)
+}
+
+// Should list an order's metafields
+func TestGetOrderMetafields(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/orders/450789469/metafields.json", "order_metafields.json"))
+	defer server.Close()
+
+	metafields, errs := mock.GetOrderMetafields(450789469)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 1, len(metafields))
+	assert.Equal(t, "order", metafields[0].OwnerResource)
+}
+
+// Should query the definitions of the owner type and flatten their type
+func TestGetMetafieldDefinitions(t *testing.T) {
+	fixture := fixtureHandler(t, "/admin/api/graphql.json", "graphql_metafield_definitions.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "PRODUCT", body.Variables["ownerType"])
+		fixture(w, r)
+	})
+	defer server.Close()
+
+	definitions, errs := mock.GetMetafieldDefinitions("PRODUCT")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(definitions))
+	assert.Equal(t, MetafieldDefinition{
+		ID:          "gid://shopify/MetafieldDefinition/1071456131",
+		Name:        "Ingredients",
+		Namespace:   "my_fields",
+		Key:         "ingredients",
+		Description: "What the product is made of",
+		Type:        "multi_line_text_field",
+		Validations: []MetafieldValidation{},
+	}, definitions[0])
+	assert.Equal(t, "weight", definitions[1].Type)
+	assert.Equal(t, "min", definitions[1].Validations[0].Name)
+}
@@ -0,0 +1,103 @@
+package shopify
+
+import (
+	"fmt"
+	"sort"
+)
+
+const metaobjectFields = `id type handle displayName updatedAt fields { key value }`
+
+const metaobjectCreateMutation = `mutation($metaobject: MetaobjectCreateInput!) {
+  metaobjectCreate(metaobject: $metaobject) {
+    metaobject { ` + metaobjectFields + ` }
+    userErrors { field message }
+  }
+}`
+
+const metaobjectQuery = `query($id: ID!) {
+  metaobject(id: $id) { ` + metaobjectFields + ` }
+}`
+
+//Metaobject is an entry of a custom content type defined by the store, e.g. a designer or a size chart
+type Metaobject struct {
+	ID string
+	// Type is the handle of the metaobject definition
+	Type        string
+	Handle      string
+	DisplayName string
+	// Fields are the values of the entry keyed by field key
+	Fields    map[string]string
+	UpdatedAt ShopTime
+}
+
+// graphQLMetaobject is a metaobject as returned by the GraphQL api
+type graphQLMetaobject struct {
+	ID          string   `json:"id"`
+	Type        string   `json:"type"`
+	Handle      string   `json:"handle"`
+	DisplayName string   `json:"displayName"`
+	UpdatedAt   ShopTime `json:"updatedAt"`
+	Fields      []struct {
+		Key   string  `json:"key"`
+		Value *string `json:"value"`
+	} `json:"fields"`
+}
+
+// toMetaobject flattens the fields of the metaobject into a map, leaving out the ones without a value
+func (metaobject graphQLMetaobject) toMetaobject() Metaobject {
+	fields := make(map[string]string)
+	for _, field := range metaobject.Fields {
+		if field.Value != nil {
+			fields[field.Key] = *field.Value
+		}
+	}
+	return Metaobject{
+		ID:          metaobject.ID,
+		Type:        metaobject.Type,
+		Handle:      metaobject.Handle,
+		DisplayName: metaobject.DisplayName,
+		Fields:      fields,
+		UpdatedAt:   metaobject.UpdatedAt,
+	}
+}
+
+//CreateMetaobject creates an entry of the metaobject definition with the given type handle, setting the
+//values of the given fields. Fields the definition doesn't have are reported in the userErrors of shopify.
+func (shop *Shopify) CreateMetaobject(typeHandle string, fields map[string]string) (Metaobject, []error) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var inputFields []map[string]interface{}
+	for _, key := range keys {
+		inputFields = append(inputFields, map[string]interface{}{"key": key, "value": fields[key]})
+	}
+	var data struct {
+		MetaobjectCreate struct {
+			Metaobject *graphQLMetaobject `json:"metaobject"`
+		} `json:"metaobjectCreate"`
+	}
+	variables := map[string]interface{}{"metaobject": map[string]interface{}{"type": typeHandle, "fields": inputFields}}
+	if errs := shop.graphQL(metaobjectCreateMutation, variables, &data); len(errs) > 0 {
+		return Metaobject{}, errs
+	}
+	if data.MetaobjectCreate.Metaobject == nil {
+		return Metaobject{}, []error{fmt.Errorf("no metaobject returned for type %q", typeHandle)}
+	}
+	return data.MetaobjectCreate.Metaobject.toMetaobject(), nil
+}
+
+//GetMetaobject returns a metaobject given its global id
+func (shop *Shopify) GetMetaobject(id string) (Metaobject, []error) {
+	var data struct {
+		Metaobject *graphQLMetaobject `json:"metaobject"`
+	}
+	if errs := shop.graphQL(metaobjectQuery, map[string]interface{}{"id": id}, &data); len(errs) > 0 {
+		return Metaobject{}, errs
+	}
+	if data.Metaobject == nil {
+		return Metaobject{}, []error{fmt.Errorf("metaobject %v not found", id)}
+	}
+	return data.Metaobject.toMetaobject(), nil
+}
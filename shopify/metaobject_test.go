@@ -0,0 +1,78 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+const designerMetaobjectJSON = `{"id": "gid://shopify/Metaobject/1234", "type": "designer", "handle": "ada-lovelace",
+	"displayName": "Ada Lovelace", "updatedAt": "2023-05-01T12:00:00Z",
+	"fields": [{"key": "name", "value": "Ada Lovelace"}, {"key": "country", "value": "UK"}, {"key": "website", "value": null}]}`
+
+// Should create the metaobject with its fields sorted by key and decode the result
+func TestCreateMetaobject(t *testing.T) {
+	var variables map[string]interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		variables = body["variables"]
+		w.Write([]byte(`{"data": {"metaobjectCreate": {"metaobject": ` + designerMetaobjectJSON + `, "userErrors": []}}}`))
+	})
+	defer server.Close()
+
+	metaobject, errs := mock.CreateMetaobject("designer", map[string]string{"name": "Ada Lovelace", "country": "UK"})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, map[string]interface{}{"metaobject": map[string]interface{}{
+		"type": "designer",
+		"fields": []interface{}{
+			map[string]interface{}{"key": "country", "value": "UK"},
+			map[string]interface{}{"key": "name", "value": "Ada Lovelace"},
+		},
+	}}, variables)
+	assert.Equal(t, "gid://shopify/Metaobject/1234", metaobject.ID)
+	assert.Equal(t, "ada-lovelace", metaobject.Handle)
+	assert.Equal(t, map[string]string{"name": "Ada Lovelace", "country": "UK"}, metaobject.Fields)
+}
+
+// Should surface the userErrors of the creation
+func TestCreateMetaobjectUserErrors(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"metaobjectCreate": {"metaobject": null,
+			"userErrors": [{"field": ["metaobject", "fields", "0"], "message": "Field definition \"age\" does not exist"}]}}}`))
+	})
+	defer server.Close()
+
+	_, errs := mock.CreateMetaobject("designer", map[string]string{"age": "36"})
+
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, ErrorMessages{"metaobject.fields.0": {`Field definition "age" does not exist`}}, findShopifyError(errs).Errors)
+}
+
+// Should get the metaobject by id and report a missing one
+func TestGetMetaobject(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["variables"]["id"] == "gid://shopify/Metaobject/1234" {
+			w.Write([]byte(`{"data": {"metaobject": ` + designerMetaobjectJSON + `}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"metaobject": null}}`))
+	})
+	defer server.Close()
+
+	metaobject, errs := mock.GetMetaobject("gid://shopify/Metaobject/1234")
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "designer", metaobject.Type)
+	assert.Equal(t, "Ada Lovelace", metaobject.DisplayName)
+	assert.Equal(t, time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC), metaobject.UpdatedAt.UTC())
+	assert.Equal(t, map[string]string{"name": "Ada Lovelace", "country": "UK"}, metaobject.Fields)
+
+	_, errs = mock.GetMetaobject("gid://shopify/Metaobject/1")
+	assert.Equal(t, "metaobject gid://shopify/Metaobject/1 not found", errs[0].Error())
+}
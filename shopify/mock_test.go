@@ -0,0 +1,67 @@
+package shopify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+)
+
+// newMockShopify creates a Shopify whose requests are answered by handler instead
+// of a real store, configured with the given options. The caller is responsible for
+// closing the returned server.
+func newMockShopify(t *testing.T, handler http.HandlerFunc, options ...Option) (*Shopify, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+
+	mock := New(serverURL.Host, "mock-key", "mock-pass", options...)
+	mock.scheme = serverURL.Scheme
+	mock.host = serverURL.Host
+	return &mock, server
+}
+
+// loadFixture reads a JSON fixture from the testdata folder
+func loadFixture(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// fixtureHandler answers requests to path with the given fixture and fails the test on any other path
+func fixtureHandler(t *testing.T, path, fixture string) http.HandlerFunc {
+	data := loadFixture(t, fixture)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			t.Errorf("unexpected request to %v, expected %v", r.URL.Path, path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}
+}
+
+// routesHandler answers each request path with its fixture and fails the test on unknown paths
+func routesHandler(t *testing.T, routes map[string]string) http.HandlerFunc {
+	handlers := make(map[string]http.HandlerFunc)
+	for path, fixture := range routes {
+		handlers[path] = fixtureHandler(t, path, fixture)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		handler, ok := handlers[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request to %v", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		handler(w, r)
+	}
+}
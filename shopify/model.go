@@ -1,18 +1,70 @@
 package shopify
 
-import "time"
+//AbandonedCheckout is a checkout the customer left before completing it
+type AbandonedCheckout struct {
+	ID                   int64      `json:"id"`
+	Token                string     `json:"token"`
+	CartToken            string     `json:"cart_token"`
+	Email                string     `json:"email"`
+	AbandonedCheckoutURL string     `json:"abandoned_checkout_url"`
+	CompletedAt          *ShopTime  `json:"completed_at"`
+	Currency             string     `json:"currency"`
+	TotalPrice           Money      `json:"total_price"`
+	LineItems            []LineItem `json:"line_items"`
+	CreatedAt            ShopTime   `json:"created_at"`
+	UpdatedAt            ShopTime   `json:"updated_at"`
+}
+
+//Address is an address to set on a resource, blank fields are left untouched
+type Address struct {
+	FirstName    string `json:"first_name,omitempty"`
+	LastName     string `json:"last_name,omitempty"`
+	Company      string `json:"company,omitempty"`
+	Address1     string `json:"address1,omitempty"`
+	Address2     string `json:"address2,omitempty"`
+	City         string `json:"city,omitempty"`
+	Province     string `json:"province,omitempty"`
+	ProvinceCode string `json:"province_code,omitempty"`
+	Country      string `json:"country,omitempty"`
+	CountryCode  string `json:"country_code,omitempty"`
+	Zip          string `json:"zip,omitempty"`
+	Phone        string `json:"phone,omitempty"`
+}
 
 //ApplicationCharge is an application charge
 type ApplicationCharge struct {
-	ConfirmationURL string    `json:"confirmation_url"`
-	CreatedAt       time.Time `json:"created_at"`
-	ID              int64     `json:"id"`
-	Name            string    `json:"name"`
-	Price           string    `json:"price"`
-	ReturnURL       string    `json:"return_url"`
-	Status          string    `json:"status"`
-	Test            string    `json:"test"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ConfirmationURL string   `json:"confirmation_url"`
+	CreatedAt       ShopTime `json:"created_at"`
+	ID              int64    `json:"id"`
+	Name            string   `json:"name"`
+	Price           Money    `json:"price"`
+	ReturnURL       string   `json:"return_url"`
+	Status          string   `json:"status"` //pending, accepted, active, declined or expired
+	Test            *bool    `json:"test"`   //test charges are not billed, null for real ones
+	UpdatedAt       ShopTime `json:"updated_at"`
+}
+
+//AppliedDiscount is a discount applied to a draft order or one of its line items
+type AppliedDiscount struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Value       string `json:"value"`
+	ValueType   string `json:"value_type"` //fixed_amount or percentage
+	Amount      string `json:"amount,omitempty"`
+}
+
+//Asset is a file of a theme, e.g. a template, a stylesheet or an image
+type Asset struct {
+	Key         string   `json:"key"`
+	Value       string   `json:"value"`      //content of text assets
+	Attachment  string   `json:"attachment"` //base64 encoded content of binary assets
+	PublicURL   string   `json:"public_url"`
+	ContentType string   `json:"content_type"`
+	Size        int      `json:"size"`
+	ThemeID     int64    `json:"theme_id"`
+	Checksum    string   `json:"checksum"`
+	CreatedAt   ShopTime `json:"created_at"`
+	UpdatedAt   ShopTime `json:"updated_at"`
 }
 
 //BillingAddress is a billing address
@@ -34,6 +86,43 @@ type BillingAddress struct {
 	Default      bool   `json:"default"`
 }
 
+//BulkOperation is an asynchronous GraphQL query, its results are a JSONL file at URL once COMPLETED
+type BulkOperation struct {
+	ID             string    `json:"id"`
+	Status         string    `json:"status"`    //CREATED, RUNNING, COMPLETED, CANCELING, CANCELED, FAILED or EXPIRED
+	ErrorCode      string    `json:"errorCode"` //set when FAILED, e.g. TIMEOUT
+	ObjectCount    int64     `json:"objectCount,string"`
+	FileSize       int64     `json:"fileSize,string"`
+	URL            string    `json:"url"`
+	PartialDataURL string    `json:"partialDataUrl"`
+	Query          string    `json:"query"`
+	CreatedAt      ShopTime  `json:"createdAt"`
+	CompletedAt    *ShopTime `json:"completedAt"`
+}
+
+//Checkout is a checkout created through the checkout API
+type Checkout struct {
+	Token         string             `json:"token"`
+	WebURL        string             `json:"web_url"`
+	Email         string             `json:"email"`
+	Currency      string             `json:"currency"`
+	LineItems     []CheckoutLineItem `json:"line_items"`
+	SubtotalPrice Money              `json:"subtotal_price"`
+	TotalPrice    Money              `json:"total_price"`
+	CompletedAt   *ShopTime          `json:"completed_at"`
+	CreatedAt     ShopTime           `json:"created_at"`
+	UpdatedAt     ShopTime           `json:"updated_at"`
+}
+
+//CheckoutLineItem is a variant to buy in a checkout
+type CheckoutLineItem struct {
+	VariantID int64  `json:"variant_id"`
+	ProductID int64  `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	Title     string `json:"title"`
+	Price     Money  `json:"price"`
+}
+
 //ClientDetails are details about a client
 type ClientDetails struct {
 	AcceptLanguage *string `json:"accept_language"` //TODO check
@@ -44,37 +133,79 @@ type ClientDetails struct {
 	UserAgent      *string `json:"user_agent"`
 }
 
+//CollectionRule is a condition products must meet to be part of a smart collection
+type CollectionRule struct {
+	Column    string `json:"column"`
+	Relation  string `json:"relation"`
+	Condition string `json:"condition"`
+}
+
+//Currency is a presentment currency enabled on a multi-currency store
+type Currency struct {
+	Currency      string   `json:"currency"`
+	RateUpdatedAt ShopTime `json:"rate_updated_at"`
+	Enabled       bool     `json:"enabled"`
+}
+
 //Customer is a customer
 type Customer struct {
-	AcceptsMarketing bool      `json:"accepts_marketing"`
-	CreatedAt        time.Time `json:"created_at"`
-	Email            string    `json:"email"`
-	ID               int64     `json:"id"`
-	FirstName        string    `json:"first_name"`
-	Note             string    `json:"note"`
-	LastName         string    `json:"last_name"`
-	OrdersCount      int       `json:"orders_count"`
-	State            string    `json:"state"`
-	TotalSpent       string    `json:"total_spent"`
-	UpdatedAt        time.Time
-	Tags             string `json:"tags"`
+	AcceptsMarketing bool     `json:"accepts_marketing"`
+	CreatedAt        ShopTime `json:"created_at"`
+	Email            string   `json:"email"`
+	ID               int64    `json:"id"`
+	FirstName        string   `json:"first_name"`
+	Note             string   `json:"note"`
+	LastName         string   `json:"last_name"`
+	OrdersCount      int      `json:"orders_count"`
+	State            string   `json:"state"`
+	TotalSpent       Money    `json:"total_spent"`
+	UpdatedAt        ShopTime `json:"updated_at"`
+	Tags             string   `json:"tags"`
+	TaxExempt        bool     `json:"tax_exempt"`
+	TaxExemptions    []string `json:"tax_exemptions"`
+}
+
+//CustomerAddress is one of the addresses of a customer
+type CustomerAddress struct {
+	ID         int64 `json:"id"`
+	CustomerID int64 `json:"customer_id"`
+	Address
+	Name    string `json:"name"`
+	Default bool   `json:"default"`
 }
 
 //Discount is a discount
 type Discount struct {
-	ID                 int64     `json:"id"`
-	DiscountType       string    `json:"discount_type"`
-	Code               string    `json:"code"`
-	Value              string    `json:"value"`
-	EndsAt             time.Time `json:"ends_at"`
-	StartsAt           time.Time `json:"starts_at"`
-	Status             string    `json:"status"`
-	MinimumOrderAmount string    `json:"minimum_order_amount"`
-	UsageLimit         int       `json:"usage_limit"`
-	AppliesToID        int64     `json:"applies_to_id"`
-	AppliesOnce        bool      `json:"applies_once"`
-	AppliesToResource  string    `json:"applies_to_resource"`
-	TimesUsed          int       `json:"times_used"`
+	ID                 int64    `json:"id"`
+	DiscountType       string   `json:"discount_type"`
+	Code               string   `json:"code"`
+	Value              string   `json:"value"`
+	EndsAt             ShopTime `json:"ends_at"`
+	StartsAt           ShopTime `json:"starts_at"`
+	Status             string   `json:"status"`
+	MinimumOrderAmount Money    `json:"minimum_order_amount"`
+	UsageLimit         int      `json:"usage_limit"`
+	AppliesToID        int64    `json:"applies_to_id"`
+	AppliesOnce        bool     `json:"applies_once"`
+	AppliesToResource  string   `json:"applies_to_resource"`
+	TimesUsed          int      `json:"times_used"`
+}
+
+//DiscountApplication is a discount applied to an order, whatever its origin
+type DiscountApplication struct {
+	// Type is one of discount_code, automatic, manual or script
+	Type string `json:"type"`
+	// Code is only set for discount_code applications
+	Code        string `json:"code"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Value is a percentage or an amount in the order's currency depending on ValueType
+	Value     string `json:"value"`
+	ValueType string `json:"value_type"` //percentage or fixed_amount
+	// AllocationMethod is across, each or one
+	AllocationMethod string `json:"allocation_method"`
+	TargetSelection  string `json:"target_selection"` //all, entitled or explicit
+	TargetType       string `json:"target_type"`      //line_item or shipping_line
 }
 
 //DiscountCode is a discount code
@@ -85,15 +216,86 @@ type DiscountCode struct {
 	Type   string `json:"type,omitempty"`
 }
 
+//DraftOrder is an order being prepared by the merchant, e.g. a quote, before it's invoiced
+type DraftOrder struct {
+	ID            int64                `json:"id"`
+	Name          string               `json:"name"`
+	Email         string               `json:"email"`
+	Status        string               `json:"status"`
+	InvoiceURL    string               `json:"invoice_url"`
+	Currency      string               `json:"currency"`
+	Note          string               `json:"note"`
+	Tags          string               `json:"tags"`
+	LineItems     []DraftOrderLineItem `json:"line_items"`
+	SubtotalPrice Money                `json:"subtotal_price"`
+	TotalTax      Money                `json:"total_tax"`
+	TotalPrice    Money                `json:"total_price"`
+	OrderID       *int64               `json:"order_id"`
+	CompletedAt   *ShopTime            `json:"completed_at"`
+	CreatedAt     ShopTime             `json:"created_at"`
+	UpdatedAt     ShopTime             `json:"updated_at"`
+}
+
+//DraftOrderLineItem is a line item of a draft order, custom items have no variant
+type DraftOrderLineItem struct {
+	ID               int64            `json:"id"`
+	VariantID        *int64           `json:"variant_id"`
+	ProductID        *int64           `json:"product_id"`
+	Title            string           `json:"title"`
+	SKU              string           `json:"sku"`
+	Price            Money            `json:"price"`
+	Quantity         int              `json:"quantity"`
+	Custom           bool             `json:"custom"`
+	Taxable          bool             `json:"taxable"`
+	RequiresShipping bool             `json:"requires_shipping"`
+	Grams            int              `json:"grams"`
+	AppliedDiscount  *AppliedDiscount `json:"applied_discount"`
+	Properties       []NoteAttribute  `json:"properties"`
+}
+
 //Fulfillment is a fulfillment
 type Fulfillment struct {
-	ID              int64     `json:"id"`
-	CreatedAt       time.Time `json:"created_at"`
-	OrderID         int64     `json:"order_id"`
-	Status          string    `json:"status"`
-	TrackingCompany string    `json:"tracking_company"`
-	TrackingNumber  string    `json:"tracking_number"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              int64      `json:"id"`
+	CreatedAt       ShopTime   `json:"created_at"`
+	OrderID         int64      `json:"order_id"`
+	Status          string     `json:"status"`
+	LineItems       []LineItem `json:"line_items"` //the quantity of each is the quantity fulfilled
+	TrackingCompany string     `json:"tracking_company"`
+	TrackingNumber  string     `json:"tracking_number"`
+	UpdatedAt       ShopTime   `json:"updated_at"`
+}
+
+//FulfillmentService is a third party warehouse fulfilling the orders of its location
+type FulfillmentService struct {
+	ID                     int64  `json:"id"`
+	Name                   string `json:"name"`
+	Handle                 string `json:"handle"`
+	LocationID             int64  `json:"location_id"`
+	InventoryManagement    bool   `json:"inventory_management"`
+	TrackingSupport        bool   `json:"tracking_support"`
+	FulfillmentOrdersOptIn bool   `json:"fulfillment_orders_opt_in"`
+	CallbackURL            string `json:"callback_url"`
+}
+
+//InventoryItem is the inventory item backing a variant
+type InventoryItem struct {
+	ID                   int64    `json:"id"`
+	SKU                  string   `json:"sku"`
+	Cost                 string   `json:"cost"` //e.g. 25.00
+	Tracked              bool     `json:"tracked"`
+	RequiresShipping     bool     `json:"requires_shipping"`
+	CountryCodeOfOrigin  *string  `json:"country_code_of_origin"`
+	HarmonizedSystemCode *string  `json:"harmonized_system_code"`
+	CreatedAt            ShopTime `json:"created_at"`
+	UpdatedAt            ShopTime `json:"updated_at"`
+}
+
+//InventoryLevel is the available quantity of an inventory item at a location
+type InventoryLevel struct {
+	InventoryItemID int64    `json:"inventory_item_id"`
+	LocationID      int64    `json:"location_id"`
+	Available       *int     `json:"available"`
+	UpdatedAt       ShopTime `json:"updated_at"`
 }
 
 //LineItem is an order line item
@@ -103,9 +305,10 @@ type LineItem struct {
 	FulfillmentStatus   *string   `json:"fulfillment_status"`
 	Grams               int       `json:"grams"`
 	ID                  int64     `json:"id"`
-	Price               string    `json:"price"` //e.g. 199.99
+	Price               Money     `json:"price"` //e.g. 199.99
+	PriceSet            *MoneySet `json:"price_set"`
 	ProductID           int64     `json:"product_id"`
-	Quantity            int       `json:"id"`
+	Quantity            int       `json:"quantity"`
 	RequiresShipping    bool      `json:"requires_shipping"`
 	SKU                 string    `json:"sku"`
 	Title               string    `json:"title"`
@@ -115,7 +318,59 @@ type LineItem struct {
 	GiftCard            *bool     `json:"gift_card"`
 	Taxable             bool      `json:"taxable"`
 	TaxLines            []TaxLine `json:"tax_line"`
-	TotalDiscount       string    `json:"total_discount"`
+	TotalDiscount       Money     `json:"total_discount"`
+}
+
+//Location is a place where inventory is stocked and orders are fulfilled from
+type Location struct {
+	ID           int64    `json:"id"`
+	Name         string   `json:"name"`
+	Address1     string   `json:"address1"`
+	Address2     string   `json:"address2"`
+	City         string   `json:"city"`
+	Zip          string   `json:"zip"`
+	Province     string   `json:"province"`
+	ProvinceCode string   `json:"province_code"`
+	Country      string   `json:"country"`
+	CountryCode  string   `json:"country_code"`
+	Phone        string   `json:"phone"`
+	Active       bool     `json:"active"`
+	Legacy       bool     `json:"legacy"` //the location belongs to a fulfillment service
+	CreatedAt    ShopTime `json:"created_at"`
+	UpdatedAt    ShopTime `json:"updated_at"`
+	// FulfillmentService is the handle of the fulfillment service of a legacy location, set by GetFulfillableLocations
+	FulfillmentService string `json:"-"`
+}
+
+//Metafield is custom data attached to a shop resource
+type Metafield struct {
+	ID            int64     `json:"id,omitempty"`
+	Namespace     string    `json:"namespace"`
+	Key           string    `json:"key"`
+	Value         string    `json:"value"`
+	Type          string    `json:"type"` //e.g. single_line_text_field
+	Description   string    `json:"description,omitempty"`
+	OwnerID       int64     `json:"owner_id,omitempty"`
+	OwnerResource string    `json:"owner_resource,omitempty"`
+	CreatedAt     *ShopTime `json:"created_at,omitempty"`
+	UpdatedAt     *ShopTime `json:"updated_at,omitempty"`
+}
+
+//MetafieldDefinition describes the metafields of a namespace and key for an owner type
+type MetafieldDefinition struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Namespace   string                `json:"namespace"`
+	Key         string                `json:"key"`
+	Description string                `json:"description"`
+	Type        string                `json:"type"` //e.g. single_line_text_field
+	Validations []MetafieldValidation `json:"validations"`
+}
+
+//MetafieldValidation is a validation rule of a metafield definition
+type MetafieldValidation struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 //NoteAttribute is a note attribute
@@ -126,49 +381,51 @@ type NoteAttribute struct {
 
 //Order is a product
 type Order struct {
-	BillingAddress         *BillingAddress  `json:"billing_address"`
-	BrowserIP              string           `json:"browser_ip"`
-	BuyerAcceptsMarketing  bool             `json:"buyer_accepts_marketing"`
-	CancelReason           *string          `json:"cancel_reason"`
-	CancelledAt            *time.Time       `json:"cancelled_at"`
-	ClientDetails          *ClientDetails   `json:"client_details"`
-	ClosedAt               *time.Time       `json:"closed_at"`
-	CreatedAt              time.Time        `json:"created_at"`
-	Currency               string           `json:"currency"`
-	Customer               *Customer        `json:"customer"`
-	DiscountCodes          *[]DiscountCode  `json:"discount_codes"`
-	Email                  string           `json:"email"`
-	FinancialStatus        string           `json:"financial_status"`
-	Fulfillments           *[]Fulfillment   `json:"fulfillments"`
-	FulfillmentStatus      string           `json:"fulfillment_status"`
-	Tags                   string           `json:"tags"`
-	ID                     int64            `json:"id"`
-	InventoryBehaviour     string           `json:"inventory_behaviour"` //used only in create
-	LandingSite            string           `json:"landing_site"`
-	LineItems              []LineItem       `json:"line_items"`
-	Name                   string           `json:"name"`
-	Note                   *string          `json:"note"`
-	NoteAttributes         *[]NoteAttribute `json:"note_attributes"`
-	Number                 int64            `json:"number"`
-	OrderNumber            int64            `json:"order_number"`
-	PaymentGatewayNames    []string         `json:"payment_gateway_names"`
-	ProcessedAt            time.Time        `json:"processed_at"`
-	ProcessingMethod       string           `json:"processing_method"`
-	ReferringSite          string           `json:"referring_site"`
-	Refunds                *[]Refund        `json:"refunds"`
-	SendReceipt            bool             `json:"send_receipt"`             //used only in create
-	SendFulfillmentReceipt bool             `json:"send_fulfillment_receipt"` //used only in create
-	ShippingAddress        *ShippingAddress `json:"shipping_address"`
-	ShippingLines          *[]ShippingLine  `json:"shipping_lines"`
-	SourceName             string           `json:"source_name"`
-	SubtotalPrice          string           `json:"subtotal_price"`
-	TaxLines               *[]TaxLine       `json:"tax_lines"`
-	TaxesIncluded          bool             `json:"taxes_included"`
-	TotalDiscounts         string           `json:"total_discounts"`
-	TotalPrice             string           `json:"total_price"`
-	TotalTax               string           `json:"total_tax"`
-	TotalWeight            float64          `json:"total_weight"`
-	UpdatedAt              time.Time        `json:"updatedAt"`
+	BillingAddress         *BillingAddress       `json:"billing_address"`
+	BrowserIP              string                `json:"browser_ip"`
+	BuyerAcceptsMarketing  bool                  `json:"buyer_accepts_marketing"`
+	CancelReason           *string               `json:"cancel_reason"`
+	CancelledAt            *ShopTime             `json:"cancelled_at"`
+	ClientDetails          *ClientDetails        `json:"client_details"`
+	ClosedAt               *ShopTime             `json:"closed_at"`
+	CreatedAt              ShopTime              `json:"created_at"`
+	Currency               string                `json:"currency"`
+	Customer               *Customer             `json:"customer"`
+	DiscountApplications   []DiscountApplication `json:"discount_applications"`
+	DiscountCodes          *[]DiscountCode       `json:"discount_codes"`
+	Email                  string                `json:"email"`
+	FinancialStatus        string                `json:"financial_status"`
+	Fulfillments           *[]Fulfillment        `json:"fulfillments"`
+	FulfillmentStatus      string                `json:"fulfillment_status"`
+	Tags                   string                `json:"tags"`
+	ID                     int64                 `json:"id"`
+	InventoryBehaviour     string                `json:"inventory_behaviour"` //used only in create
+	LandingSite            string                `json:"landing_site"`
+	LineItems              []LineItem            `json:"line_items"`
+	Name                   string                `json:"name"`
+	Note                   *string               `json:"note"`
+	NoteAttributes         *[]NoteAttribute      `json:"note_attributes"`
+	Number                 int64                 `json:"number"`
+	OrderNumber            int64                 `json:"order_number"`
+	PaymentGatewayNames    []string              `json:"payment_gateway_names"`
+	ProcessedAt            ShopTime              `json:"processed_at"`
+	ProcessingMethod       string                `json:"processing_method"`
+	ReferringSite          string                `json:"referring_site"`
+	Refunds                *[]Refund             `json:"refunds"`
+	SendReceipt            bool                  `json:"send_receipt"`             //used only in create
+	SendFulfillmentReceipt bool                  `json:"send_fulfillment_receipt"` //used only in create
+	ShippingAddress        *ShippingAddress      `json:"shipping_address"`
+	ShippingLines          []ShippingLine        `json:"shipping_lines"`
+	SourceName             string                `json:"source_name"`
+	SubtotalPrice          Money                 `json:"subtotal_price"`
+	TaxLines               *[]TaxLine            `json:"tax_lines"`
+	TaxesIncluded          bool                  `json:"taxes_included"`
+	TotalDiscounts         Money                 `json:"total_discounts"`
+	TotalPrice             Money                 `json:"total_price"`
+	TotalPriceSet          *MoneySet             `json:"total_price_set"`
+	TotalTax               Money                 `json:"total_tax"`
+	TotalWeight            float64               `json:"total_weight"`
+	UpdatedAt              ShopTime              `json:"updated_at"`
 }
 
 //PaymentDetails are the details about a payment
@@ -180,56 +437,130 @@ type PaymentDetails struct {
 	CreditCardCompany string  `json:"credit_card_company"`
 }
 
+//PaymentGateway is a payment provider set up on the store, e.g. shopify_payments or paypal
+type PaymentGateway struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	ProviderID int64     `json:"provider_id"`
+	Enabled    bool      `json:"enabled"`
+	Sandbox    bool      `json:"sandbox"`
+	CreatedAt  *ShopTime `json:"created_at"`
+	UpdatedAt  *ShopTime `json:"updated_at"`
+}
+
+//Policy is one of the shop's legal policies
+type Policy struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body"`
+	URL       string   `json:"url"`
+	Handle    string   `json:"handle"`
+	CreatedAt ShopTime `json:"created_at"`
+	UpdatedAt ShopTime `json:"updated_at"`
+}
+
 //Product is a product
 type Product struct {
 	BodyHTML                       string                   `json:"body_html"`
-	CreatedAt                      time.Time                `json:"created_at"`
+	CreatedAt                      ShopTime                 `json:"created_at"`
 	Handle                         string                   `json:"handle"`
 	ID                             int64                    `json:"id"`
 	Images                         []ProductImage           `json:"images"`
 	Options                        []map[string]interface{} `json:"options"`
 	ProductType                    string                   `json:"product_type"`
-	PublishedAt                    *time.Time               `json:"published_at"`
+	PublishedAt                    *ShopTime                `json:"published_at"`
 	PublishedScope                 string                   `json:"published_scope"`
+	Status                         string                   `json:"status"` //active, archived or draft
 	Tags                           string                   `json:"tags"`
 	TemplateSuffix                 string                   `json:"template_suffix"`
 	Title                          string                   `json:"title"`
 	MetafieldsGlobalTitleTag       string                   `json:"metafields_global_title_tag"`
 	MetafieldsGlobalDescriptionTag string                   `json:"metafields_global_description_tag"`
-	UpdatedAt                      time.Time                `json:"updatedAt"`
+	UpdatedAt                      ShopTime                 `json:"updated_at"`
 	Variants                       []Variant                `json:"variants"`
 	Vendor                         string                   `json:"vendor"`
 }
 
 //ProductImage is a product's image
 type ProductImage struct {
-	CreatedAt  time.Time `json:"created_at"`
-	ID         int64     `json:"id"`
-	Position   int       `json:"position"`
-	ProductID  int64     `json:"product_id"`
-	VariantIDs []int64   `json:"variant_ids"`
-	Src        string    `json:"src"`
-	UpdatedAt  time.Time `json:"id"`
+	CreatedAt  ShopTime `json:"created_at"`
+	ID         int64    `json:"id"`
+	Position   int      `json:"position"`
+	ProductID  int64    `json:"product_id"`
+	VariantIDs []int64  `json:"variant_ids"`
+	Src        string   `json:"src"`
+	UpdatedAt  ShopTime `json:"updated_at"`
+}
+
+//RecurringApplicationCharge is a monthly subscription to the app. A capped amount with terms enables usage
+//charges on top of the subscription price, up to the cap each billing period.
+type RecurringApplicationCharge struct {
+	ID               int64    `json:"id"`
+	Name             string   `json:"name"`
+	Price            Money    `json:"price"`
+	ReturnURL        string   `json:"return_url"`
+	ConfirmationURL  string   `json:"confirmation_url"`
+	Status           string   `json:"status"` //pending, accepted, active, declined, expired, frozen or cancelled
+	Test             *bool    `json:"test"`
+	TrialDays        int      `json:"trial_days"`
+	TrialEndsOn      string   `json:"trial_ends_on"` //e.g. 2024-01-15
+	CappedAmount     Money    `json:"capped_amount"`
+	Terms            string   `json:"terms"` //describes the usage charges to the merchant
+	BalanceUsed      Money    `json:"balance_used"`
+	BalanceRemaining Money    `json:"balance_remaining"`
+	ActivatedOn      string   `json:"activated_on"`
+	BillingOn        string   `json:"billing_on"`
+	CancelledOn      string   `json:"cancelled_on"`
+	CreatedAt        ShopTime `json:"created_at"`
+	UpdatedAt        ShopTime `json:"updated_at"`
+}
+
+//Redirect is a URL redirect of the online store
+type Redirect struct {
+	ID     int64  `json:"id"`
+	Path   string `json:"path"`
+	Target string `json:"target"`
 }
 
 //Refund is a refund
 type Refund struct {
-	CreatedAt       time.Time        `json:"created_at"`
+	CreatedAt       ShopTime         `json:"created_at"`
 	ID              int64            `json:"id"`
 	Note            string           `json:"note"`
 	RefundLineItems []RefundLineItem `json:"refund_line_items"`
 	Restock         bool             `json:"restock"`
-	//Transactions    []Transaction    `json:"transactions"`
-	UserID  int64 `json:"user_id"`
-	OrderID int64 `json:"order_id"`
+	Transactions    []Transaction    `json:"transactions"`
+	UserID          int64            `json:"user_id"`
+	OrderID         int64            `json:"order_id"`
 }
 
 //RefundLineItem is a refund line item
 type RefundLineItem struct {
-	ID         int64    `json:"id"`
-	LineItem   LineItem `json:"line_item"`
-	LineItemID int64    `json:"line_item_id"`
-	Quantity   int      `json:"quantity"`
+	ID          int64    `json:"id"`
+	LineItem    LineItem `json:"line_item"`
+	LineItemID  int64    `json:"line_item_id"`
+	Quantity    int      `json:"quantity"`
+	RestockType string   `json:"restock_type"` //one of the RestockType constants
+	LocationID  *int64   `json:"location_id"`  //required to restock with cancel or return
+	Subtotal    Money    `json:"subtotal"`
+	TotalTax    Money    `json:"total_tax"`
+}
+
+//Shop is the store's configuration
+type Shop struct {
+	ID              int64    `json:"id"`
+	Name            string   `json:"name"`
+	Email           string   `json:"email"`
+	Domain          string   `json:"domain"`
+	MyshopifyDomain string   `json:"myshopify_domain"`
+	Currency        string   `json:"currency"`
+	CountryCode     string   `json:"country_code"`
+	PrimaryLocale   string   `json:"primary_locale"`
+	Timezone        string   `json:"timezone"`
+	IANATimezone    string   `json:"iana_timezone"`
+	PlanName        string   `json:"plan_name"`
+	MoneyFormat     string   `json:"money_format"`
+	CreatedAt       ShopTime `json:"created_at"`
+	UpdatedAt       ShopTime `json:"updated_at"`
 }
 
 //ShippingAddress is a billing address
@@ -253,63 +584,126 @@ type ShippingAddress struct {
 
 //ShippingLine is a shipping line
 type ShippingLine struct {
-	Code     string    `json:"code"`
-	Price    string    `json:"price"`
-	Source   string    `json:"source"`
-	Title    string    `json:"title"`
-	TaxLines []TaxLine `json:"tax_lines"`
+	ID                int64     `json:"id"`
+	Code              string    `json:"code"`
+	Price             Money     `json:"price"`
+	Source            string    `json:"source"`
+	Title             string    `json:"title"`
+	CarrierIdentifier string    `json:"carrier_identifier"` //empty for shipping rates not calculated by a carrier service
+	TaxLines          []TaxLine `json:"tax_lines"`
+}
+
+//ShippingRate is a shipping rate available to a checkout
+type ShippingRate struct {
+	ID            string     `json:"id"`
+	Handle        string     `json:"handle"`
+	Title         string     `json:"title"`
+	Price         Money      `json:"price"`
+	PhoneRequired bool       `json:"phone_required"`
+	DeliveryRange []ShopTime `json:"delivery_range"`
+}
+
+//SmartCollection is a collection whose products are selected by rules
+type SmartCollection struct {
+	ID             int64            `json:"id"`
+	Handle         string           `json:"handle"`
+	Title          string           `json:"title"`
+	BodyHTML       string           `json:"body_html"`
+	Disjunctive    bool             `json:"disjunctive"`
+	Rules          []CollectionRule `json:"rules"`
+	SortOrder      string           `json:"sort_order"`
+	TemplateSuffix string           `json:"template_suffix"`
+	PublishedAt    *ShopTime        `json:"published_at"`
+	UpdatedAt      ShopTime         `json:"updated_at"`
 }
 
 //TaxLine is a tax line
 type TaxLine struct {
 	Title string  `json:"title"`
-	Price float64 `json:"price"`
+	Price Money   `json:"price"`
 	Rate  float64 `json:"rate"`
 }
 
+//Theme is a theme of the online store, the published one has the main role
+type Theme struct {
+	ID           int64    `json:"id"`
+	Name         string   `json:"name"`
+	Role         string   `json:"role"` //main, unpublished, demo or development
+	Previewable  bool     `json:"previewable"`
+	Processing   bool     `json:"processing"`
+	ThemeStoreID *int64   `json:"theme_store_id"`
+	CreatedAt    ShopTime `json:"created_at"`
+	UpdatedAt    ShopTime `json:"updated_at"`
+}
+
 //Transaction is a transaction
 type Transaction struct {
-	ID            int64     `json:"id"`
-	OrderID       int64     `json:"orderId"`
-	Amount        string    `json:"amount"`
-	Kind          string    `json:"kind"`
-	Authorization *string   `json:"authorization"`
-	Message       string    `json:"message"`
-	CreatedAt     time.Time `json:"created_at"`
-	DeviceID      *string   `json:"device_id"`
-	Gateway       string    `json:"gateway"`
-	SourceName    string    `json:"source_name"`
+	ID            int64    `json:"id"`
+	OrderID       int64    `json:"orderId"`
+	ParentID      *int64   `json:"parent_id"`
+	Amount        Money    `json:"amount"`
+	Kind          string   `json:"kind"`
+	Authorization *string  `json:"authorization"`
+	Message       string   `json:"message"`
+	CreatedAt     ShopTime `json:"created_at"`
+	DeviceID      *string  `json:"device_id"`
+	Gateway       string   `json:"gateway"`
+	SourceName    string   `json:"source_name"`
 	//PaymentDetails PaymentDetails `json:"payment_details"`
-	Receipt   string `json:"receipt"`
-	ErrorCode string `json:"error_code"`
-	Status    string `json:"status"`
-	Test      bool   `json:"test"`
-	UserID    *int64 `json:"userId"`
-	Currency  string `json:"currency"`
+	Receipt   map[string]interface{} `json:"receipt"`
+	ErrorCode string                 `json:"error_code"`
+	Status    string                 `json:"status"`
+	Test      bool                   `json:"test"`
+	UserID    *int64                 `json:"userId"`
+	Currency  string                 `json:"currency"`
+}
+
+//UsageCharge is a charge billed against the capped amount of a RecurringApplicationCharge
+type UsageCharge struct {
+	ID                           int64    `json:"id"`
+	Description                  string   `json:"description"`
+	Price                        Money    `json:"price"`
+	RecurringApplicationChargeID int64    `json:"recurring_application_charge_id"`
+	BalanceUsed                  Money    `json:"balance_used"`
+	BalanceRemaining             Money    `json:"balance_remaining"`
+	CreatedAt                    ShopTime `json:"created_at"`
 }
 
 //Variant is a product's variant
 type Variant struct {
-	BarCode             string    `json:"bar_code"`
-	CompareAtPrice      string    `json:"compare_at_price"`
-	CreatedAt           time.Time `json:"created_at"`
-	FulfillmentService  string    `json:"fulfillment_service"`
-	Grams               float64   `json:"grams"`
-	Weight              float64   `json:"weight"`
-	WeightUnit          string    `json:"weight_unit"`
-	ID                  int64     `json:"id"`
-	InventoryManagement string    `json:"inventory_management"`
-	InventoryPolicy     string    `json:"inventory_policy"`
-	InventoryQuantity   int       `json:"inventory_quantity"`
-	Option1             string    `json:"option1"`
-	Option2             string    `json:"option2"`
-	Option3             string    `json:"option3"`
-	Position            int       `json:"position"`
-	Price               string    `json:"price"`
-	ProductID           int64     `json:"product_id"`
-	RequiresShipping    bool      `json:"requires_shipping"`
-	SKU                 string    `json:"sku"`
-	Taxable             bool      `json:"taxable"`
-	Title               string    `json:"title"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	BarCode             string   `json:"barcode"`
+	CompareAtPrice      Money    `json:"compare_at_price"`
+	CreatedAt           ShopTime `json:"created_at"`
+	FulfillmentService  string   `json:"fulfillment_service"`
+	Grams               float64  `json:"grams"`
+	Weight              float64  `json:"weight"`
+	WeightUnit          string   `json:"weight_unit"`
+	ID                  int64    `json:"id"`
+	InventoryItemID     int64    `json:"inventory_item_id"`
+	InventoryManagement string   `json:"inventory_management"`
+	InventoryPolicy     string   `json:"inventory_policy"`
+	InventoryQuantity   int      `json:"inventory_quantity"`
+	Option1             string   `json:"option1"`
+	Option2             string   `json:"option2"`
+	Option3             string   `json:"option3"`
+	Position            int      `json:"position"`
+	Price               Money    `json:"price"`
+	ProductID           int64    `json:"product_id"`
+	RequiresShipping    bool     `json:"requires_shipping"`
+	SKU                 string   `json:"sku"`
+	Taxable             bool     `json:"taxable"`
+	Title               string   `json:"title"`
+	UpdatedAt           ShopTime `json:"updated_at"`
+}
+
+//Webhook is a webhook subscription
+type Webhook struct {
+	Address             string   `json:"address"`
+	CreatedAt           ShopTime `json:"created_at"`
+	Fields              []string `json:"fields"`
+	Format              string   `json:"format"`
+	ID                  int64    `json:"id"`
+	MetafieldNamespaces []string `json:"metafield_namespaces"`
+	Topic               string   `json:"topic"`
+	UpdatedAt           ShopTime `json:"updated_at"`
 }
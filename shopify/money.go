@@ -0,0 +1,81 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+//Money is an amount of money. Shopify sends it either as a plain string like "19.99" or,
+//inside price sets, as an {"amount", "currency_code"} object; both decode into Money.
+type Money struct {
+	// Amount keeps shopify's decimal representation, e.g. 19.99
+	Amount string
+	// CurrencyCode is only known when shopify sent the object form
+	CurrencyCode string
+}
+
+//MoneySet is a price expressed in the shop and the presentment currencies
+type MoneySet struct {
+	ShopMoney        Money `json:"shop_money"`
+	PresentmentMoney Money `json:"presentment_money"`
+}
+
+type moneyObject struct {
+	Amount       json.Number `json:"amount"`
+	CurrencyCode string      `json:"currency_code"`
+	// GraphQL's MoneyV2 spells it in camel case
+	GraphQLCurrencyCode string `json:"currencyCode"`
+}
+
+//UnmarshalJSON decodes the string, number and object forms of a money field, null leaves it empty
+func (money *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*money = Money{}
+	case len(data) > 0 && data[0] == '"':
+		var amount string
+		if err := json.Unmarshal(data, &amount); err != nil {
+			return err
+		}
+		*money = Money{Amount: amount}
+	case len(data) > 0 && data[0] == '{':
+		var object moneyObject
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		*money = Money{Amount: object.Amount.String(), CurrencyCode: object.CurrencyCode}
+		if money.CurrencyCode == "" {
+			money.CurrencyCode = object.GraphQLCurrencyCode
+		}
+	default:
+		var amount json.Number
+		if err := json.Unmarshal(data, &amount); err != nil {
+			return fmt.Errorf("shopify: invalid money %s", data)
+		}
+		*money = Money{Amount: amount.String()}
+	}
+	return nil
+}
+
+//MarshalJSON encodes the money as shopify's plain string, or as an object when it has a currency
+func (money Money) MarshalJSON() ([]byte, error) {
+	if money.CurrencyCode != "" {
+		return json.Marshal(struct {
+			Amount       string `json:"amount"`
+			CurrencyCode string `json:"currency_code"`
+		}{money.Amount, money.CurrencyCode})
+	}
+	if money.Amount == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(money.Amount)
+}
+
+func (money Money) String() string {
+	if money.CurrencyCode == "" {
+		return money.Amount
+	}
+	return money.Amount + " " + money.CurrencyCode
+}
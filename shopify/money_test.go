@@ -0,0 +1,54 @@
+package shopify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should decode the string form of a money field
+func TestMoneyUnmarshalString(t *testing.T) {
+	var variant Variant
+	err := json.Unmarshal([]byte(`{"price": "19.99", "compare_at_price": null}`), &variant)
+
+	assert.T(t, err == nil, err)
+	assert.Equal(t, Money{Amount: "19.99"}, variant.Price)
+	assert.Equal(t, Money{}, variant.CompareAtPrice)
+}
+
+// Should decode the object form used by price sets
+func TestMoneyUnmarshalObject(t *testing.T) {
+	var lineItem LineItem
+	err := json.Unmarshal([]byte(`{"price": "199.00", "price_set": {
+		"shop_money": {"amount": "199.00", "currency_code": "USD"},
+		"presentment_money": {"amount": "173.30", "currency_code": "EUR"}
+	}}`), &lineItem)
+
+	assert.T(t, err == nil, err)
+	assert.Equal(t, Money{Amount: "199.00"}, lineItem.Price)
+	assert.Equal(t, Money{Amount: "199.00", CurrencyCode: "USD"}, lineItem.PriceSet.ShopMoney)
+	assert.Equal(t, Money{Amount: "173.30", CurrencyCode: "EUR"}, lineItem.PriceSet.PresentmentMoney)
+	assert.Equal(t, "173.30 EUR", lineItem.PriceSet.PresentmentMoney.String())
+}
+
+// Should decode numbers without losing their representation
+func TestMoneyUnmarshalNumber(t *testing.T) {
+	var taxLine TaxLine
+	err := json.Unmarshal([]byte(`{"title": "State Tax", "price": 13.50, "rate": 0.06}`), &taxLine)
+
+	assert.T(t, err == nil, err)
+	assert.Equal(t, "13.50", taxLine.Price.Amount)
+}
+
+// Should encode money back into the form shopify expects
+func TestMoneyMarshal(t *testing.T) {
+	data, _ := json.Marshal(Money{Amount: "19.99"})
+	assert.Equal(t, `"19.99"`, string(data))
+
+	data, _ = json.Marshal(Money{})
+	assert.Equal(t, `null`, string(data))
+
+	data, _ = json.Marshal(Money{Amount: "19.99", CurrencyCode: "CAD"})
+	assert.Equal(t, `{"amount":"19.99","currency_code":"CAD"}`, string(data))
+}
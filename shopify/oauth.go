@@ -0,0 +1,15 @@
+package shopify
+
+//GetAccessScopes returns the handles of the scopes granted to the current credentials, e.g. read_products
+func (shop *Shopify) GetAccessScopes() ([]string, []error) {
+	var accessScopes AccessScopesResponse
+	_, response, errors := shop.send("GET", shop.createOAuthURL("access_scopes", nil), nil)
+	if err := unmarshal(response, errors, &accessScopes); len(err) > 0 {
+		return nil, err
+	}
+	scopes := make([]string, 0, len(accessScopes.AccessScopes))
+	for _, scope := range accessScopes.AccessScopes {
+		scopes = append(scopes, scope.Handle)
+	}
+	return scopes, nil
+}
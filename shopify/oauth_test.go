@@ -0,0 +1,19 @@
+package shopify
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should return the granted scope handles from the oauth endpoint
+func TestGetAccessScopes(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/oauth/access_scopes.json", "access_scopes.json"),
+		WithAdminPrefix("admin/api/2019-10"))
+	defer server.Close()
+
+	scopes, errs := mock.GetAccessScopes()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"read_products", "write_orders", "read_orders"}, scopes)
+}
@@ -1,11 +1,18 @@
 package shopify
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+	"time"
+)
 
 var emptyBody = make(map[string]string)
 
 //GetOrders returns all the orders
 func (shop *Shopify) GetOrders(parameters map[string]string) ([]Order, []error) {
+	if err := shop.checkLimit("orders", parameters); err != nil {
+		return nil, []error{err}
+	}
 	var orders OrdersResponse
 	response, errors := shop.GetWithParameters("orders", parameters)
 	if err := unmarshal(response, errors, &orders); len(err) > 0 {
@@ -24,6 +31,67 @@ func (shop *Shopify) GetOrder(orderID int64) (*Order, []error) {
 	return &orderResponse.Order, nil
 }
 
+//GetOrderByName returns the order with the given human-facing name, including the store's prefix
+//and suffix, e.g. "#1001". Orders of any status are searched.
+func (shop *Shopify) GetOrderByName(name string) (*Order, []error) {
+	orders, errs := shop.GetOrders(map[string]string{"name": name, "status": "any"})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	// shopify also matches the names the given one is part of
+	for _, order := range orders {
+		if order.Name == name {
+			return &order, nil
+		}
+	}
+	return nil, []error{fmt.Errorf("no order found with name %q", name)}
+}
+
+//GetOrderDiscounts returns the discounts applied to an order, e.g. a discount code or a manual discount
+//added by the staff, fetching only the order's discount applications
+func (shop *Shopify) GetOrderDiscounts(orderID int64) ([]DiscountApplication, []error) {
+	var orderResponse OrderResponse
+	response, errors := shop.GetWithParameters(fmt.Sprintf("orders/%v", orderID), map[string]string{"fields": "id,discount_applications"})
+	if err := unmarshal(response, errors, &orderResponse); len(err) > 0 {
+		return nil, err
+	}
+	return orderResponse.Order.DiscountApplications, nil
+}
+
+//GetLineItemFulfillmentStatus returns the fulfillment status of each line item of an order, keyed by line
+//item id: "fulfilled", "partial" or "unfulfilled" given the quantities shipped by its successful fulfillments
+func (shop *Shopify) GetLineItemFulfillmentStatus(orderID int64) (map[int64]string, []error) {
+	var orderResponse OrderResponse
+	response, errors := shop.GetWithParameters(fmt.Sprintf("orders/%v", orderID), map[string]string{"fields": "id,line_items,fulfillments"})
+	if err := unmarshal(response, errors, &orderResponse); len(err) > 0 {
+		return nil, err
+	}
+	fulfilled := make(map[int64]int)
+	if orderResponse.Order.Fulfillments != nil {
+		for _, fulfillment := range *orderResponse.Order.Fulfillments {
+			// cancelled and failed fulfillments shipped nothing
+			if fulfillment.Status != "success" && fulfillment.Status != "pending" && fulfillment.Status != "open" {
+				continue
+			}
+			for _, item := range fulfillment.LineItems {
+				fulfilled[item.ID] += item.Quantity
+			}
+		}
+	}
+	statuses := make(map[int64]string, len(orderResponse.Order.LineItems))
+	for _, item := range orderResponse.Order.LineItems {
+		switch quantity := fulfilled[item.ID]; {
+		case quantity >= item.Quantity:
+			statuses[item.ID] = "fulfilled"
+		case quantity > 0:
+			statuses[item.ID] = "partial"
+		default:
+			statuses[item.ID] = "unfulfilled"
+		}
+	}
+	return statuses, nil
+}
+
 //CloseOrder closes an order
 func (shop *Shopify) CloseOrder(orderID int64) (*Order, []error) {
 	var orderResponse OrderResponse
@@ -95,6 +163,106 @@ func (shop *Shopify) GetOrderTransactions(orderID int64) ([]Transaction, []error
 	return transactionsResponse.Transactions, nil
 }
 
+//TransactionNode is a transaction with the ones referencing it as their parent, e.g. the captures of an
+//authorization or the refunds of a capture
+type TransactionNode struct {
+	Transaction Transaction
+	Children    []TransactionNode
+}
+
+//GetOrderTransactionTree returns the order's transactions nested under their parent, in the order shopify
+//lists them. Transactions whose parent is not among the order's ones are kept as roots.
+func (shop *Shopify) GetOrderTransactionTree(orderID int64) ([]TransactionNode, []error) {
+	transactions, errs := shop.GetOrderTransactions(orderID)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	ids := make(map[int64]bool)
+	for _, transaction := range transactions {
+		ids[transaction.ID] = true
+	}
+	children := make(map[int64][]Transaction)
+	var roots []Transaction
+	for _, transaction := range transactions {
+		if transaction.ParentID == nil || !ids[*transaction.ParentID] || *transaction.ParentID == transaction.ID {
+			roots = append(roots, transaction)
+			continue
+		}
+		children[*transaction.ParentID] = append(children[*transaction.ParentID], transaction)
+	}
+	return transactionNodes(roots, children), nil
+}
+
+// transactionNodes builds the nodes of the given transactions, removing the children it uses so that a
+// parent_id cycle can't recurse forever
+func transactionNodes(transactions []Transaction, children map[int64][]Transaction) []TransactionNode {
+	nodes := make([]TransactionNode, 0, len(transactions))
+	for _, transaction := range transactions {
+		descendants := children[transaction.ID]
+		delete(children, transaction.ID)
+		nodes = append(nodes, TransactionNode{Transaction: transaction, Children: transactionNodes(descendants, children)})
+	}
+	return nodes
+}
+
+//GetOrderGateways returns the distinct payment gateways of an order, e.g. shopify_payments or gift_card.
+//They are read from the order's payment_gateway_names, or from its transactions when it has none.
+func (shop *Shopify) GetOrderGateways(orderID int64) ([]string, []error) {
+	order, errs := shop.GetOrder(orderID)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	gateways := order.PaymentGatewayNames
+	if len(gateways) == 0 {
+		transactions, errs := shop.GetOrderTransactions(orderID)
+		if len(errs) > 0 {
+			return nil, errs
+		}
+		for _, transaction := range transactions {
+			gateways = append(gateways, transaction.Gateway)
+		}
+	}
+	seen := make(map[string]bool)
+	distinct := []string{}
+	for _, gateway := range gateways {
+		if gateway != "" && !seen[gateway] {
+			seen[gateway] = true
+			distinct = append(distinct, gateway)
+		}
+	}
+	return distinct, nil
+}
+
+//GetTransactionsForOrders returns the transactions of several orders keyed by order id.
+//The orders are fetched concurrently through the rate limiter, an order that fails is left
+//out of the map and its error is returned without affecting the others.
+func (shop *Shopify) GetTransactionsForOrders(orderIDs []int64) (map[int64][]Transaction, []error) {
+	var (
+		mu           sync.Mutex
+		wg           sync.WaitGroup
+		errs         []error
+		transactions = make(map[int64][]Transaction)
+	)
+	for _, orderID := range orderIDs {
+		wg.Add(1)
+		go func(orderID int64) {
+			defer wg.Done()
+			orderTransactions, err := shop.GetOrderTransactions(orderID)
+			mu.Lock()
+			defer mu.Unlock()
+			if len(err) > 0 {
+				for _, e := range err {
+					errs = append(errs, fmt.Errorf("order %v: %w", orderID, e))
+				}
+				return
+			}
+			transactions[orderID] = orderTransactions
+		}(orderID)
+	}
+	wg.Wait()
+	return transactions, errs
+}
+
 //GetOrderTransactionsCount returns the order's transactions count
 func (shop *Shopify) GetOrderTransactionsCount(orderID int64) (int, []error) {
 	var count CountResponse
@@ -114,3 +282,94 @@ func (shop *Shopify) GetOrdersCount() (int, []error) {
 	}
 	return ordersCount.Count, nil
 }
+
+//CountOrdersByFinancialStatus returns how many orders, of any status, have each of the given financial
+//statuses, e.g. paid or refunded. The counts are fetched concurrently through the rate limiter, a status
+//that fails is left out of the map and its error is returned without affecting the others.
+func (shop *Shopify) CountOrdersByFinancialStatus(statuses []string) (map[string]int, []error) {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		errs   []error
+		counts = make(map[string]int)
+	)
+	for _, status := range statuses {
+		wg.Add(1)
+		go func(status string) {
+			defer wg.Done()
+			var ordersCount CountResponse
+			response, errors := shop.GetWithParameters("orders/count", map[string]string{"status": "any", "financial_status": status})
+			err := unmarshal(response, errors, &ordersCount)
+			mu.Lock()
+			defer mu.Unlock()
+			if len(err) > 0 {
+				for _, e := range err {
+					errs = append(errs, fmt.Errorf("financial status %v: %w", status, e))
+				}
+				return
+			}
+			counts[status] = ordersCount.Count
+		}(status)
+	}
+	wg.Wait()
+	return counts, errs
+}
+
+//StreamOrders calls fn for every order matching parameters, fetching them one page at a time
+//so that only a single page is held in memory. It stops on the first error returned by fn.
+func (shop *Shopify) StreamOrders(parameters map[string]string, fn func(order Order) error) []error {
+	return shop.paginate("orders", parameters, func(page []byte) []error {
+		var orders OrdersResponse
+		if err := unmarshal(page, nil, &orders); len(err) > 0 {
+			return err
+		}
+		for _, order := range orders.Orders {
+			if err := fn(order); err != nil {
+				return []error{err}
+			}
+		}
+		return nil
+	})
+}
+
+//GetOrdersContainingVariant returns the orders matching parameters with a line item of the given variant.
+//Shopify can't filter orders by variant, so every matching order is fetched and filtered client side:
+//bound the scan with created_at_min and created_at_max.
+func (shop *Shopify) GetOrdersContainingVariant(variantID int64, parameters map[string]string) ([]Order, []error) {
+	var orders []Order
+	errs := shop.StreamOrders(parameters, func(order Order) error {
+		for _, lineItem := range order.LineItems {
+			if lineItem.VariantID == variantID {
+				orders = append(orders, order)
+				break
+			}
+		}
+		return nil
+	})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return orders, nil
+}
+
+//CountOrdersByDateRange returns how many orders, of any status, were created between from and to.
+//Dates are sent in the store's timezone.
+func (shop *Shopify) CountOrdersByDateRange(from, to time.Time) (int, []error) {
+	if !from.Before(to) {
+		return 0, []error{fmt.Errorf("invalid date range, %v is not before %v", from, to)}
+	}
+	location, errs := shop.timezone()
+	if len(errs) > 0 {
+		return 0, errs
+	}
+	var ordersCount CountResponse
+	response, errors := shop.GetWithParameters("orders/count", map[string]string{
+		"status":         "any",
+		"created_at_min": from.In(location).Format(time.RFC3339),
+		"created_at_max": to.In(location).Format(time.RFC3339),
+	})
+	if err := unmarshal(response, errors, &ordersCount); len(err) > 0 {
+		return 0, err
+	}
+	return ordersCount.Count, nil
+}
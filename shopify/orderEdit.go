@@ -0,0 +1,160 @@
+package shopify
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const orderEditBeginMutation = `mutation($id: ID!) {
+  orderEditBegin(id: $id) {
+    calculatedOrder { id }
+    userErrors { field message }
+  }
+}`
+
+const orderEditAddVariantMutation = `mutation($id: ID!, $variantId: ID!, $quantity: Int!) {
+  orderEditAddVariant(id: $id, variantId: $variantId, quantity: $quantity) {
+    calculatedLineItem { id }
+    userErrors { field message }
+  }
+}`
+
+const orderEditCommitMutation = `mutation($id: ID!, $notifyCustomer: Boolean) {
+  orderEditCommit(id: $id, notifyCustomer: $notifyCustomer) {
+    order { id }
+    userErrors { field message }
+  }
+}`
+
+const calculatedOrderQuery = `query($id: ID!) {
+  node(id: $id) {
+    ... on CalculatedOrder {
+      originalOrder { totalPriceSet { shopMoney { amount currencyCode } } }
+      totalPriceSet { shopMoney { amount currencyCode } }
+      totalOutstandingSet { shopMoney { amount currencyCode } }
+      addedLineItems(first: 250) { edges { node { id title sku quantity } } }
+      lineItems(first: 250) { edges { node { id title sku quantity editableQuantityBeforeChanges } } }
+    }
+  }
+}`
+
+//OrderEditSummary previews the changes staged on a calculated order before they are committed
+type OrderEditSummary struct {
+	// AddedLineItems are the line items added by the edit
+	AddedLineItems []OrderEditLineItem
+	// RemovedLineItems are the existing line items whose quantity the edit lowers, by the removed quantity
+	RemovedLineItems []OrderEditLineItem
+	OriginalTotal    Money
+	NewTotal         Money
+	// TotalDifference is NewTotal minus OriginalTotal, negative when the edit lowers the total
+	TotalDifference Money
+	// TotalOutstanding is what the customer owes after the edit, negative when they are owed a refund
+	TotalOutstanding Money
+}
+
+//OrderEditLineItem is a line item changed by an order edit
+type OrderEditLineItem struct {
+	ID       string
+	Title    string
+	SKU      string
+	Quantity int
+}
+
+//BeginOrderEdit starts editing an order and returns the id of the calculated order the changes are
+//staged on, until CommitOrderEdit applies them
+func (shop *Shopify) BeginOrderEdit(orderID int64) (calculatedOrderID string, errs []error) {
+	var data struct {
+		OrderEditBegin struct {
+			CalculatedOrder *struct {
+				ID string `json:"id"`
+			} `json:"calculatedOrder"`
+		} `json:"orderEditBegin"`
+	}
+	variables := map[string]interface{}{"id": GlobalID("Order", orderID)}
+	if errs := shop.graphQL(orderEditBeginMutation, variables, &data); len(errs) > 0 {
+		return "", errs
+	}
+	if data.OrderEditBegin.CalculatedOrder == nil {
+		return "", []error{fmt.Errorf("no calculated order returned for order %v", orderID)}
+	}
+	return data.OrderEditBegin.CalculatedOrder.ID, nil
+}
+
+//OrderEditAddVariant stages the addition of quantity units of a variant, given by its GraphQL id,
+//to the calculated order
+func (shop *Shopify) OrderEditAddVariant(calculatedOrderID, variantID string, quantity int) []error {
+	if quantity < 1 {
+		return []error{fmt.Errorf("invalid quantity %d", quantity)}
+	}
+	variables := map[string]interface{}{"id": calculatedOrderID, "variantId": variantID, "quantity": quantity}
+	return shop.graphQL(orderEditAddVariantMutation, variables, nil)
+}
+
+//CommitOrderEdit applies the changes staged on the calculated order, optionally emailing the customer
+func (shop *Shopify) CommitOrderEdit(calculatedOrderID string, notify bool) []error {
+	variables := map[string]interface{}{"id": calculatedOrderID, "notifyCustomer": notify}
+	return shop.graphQL(orderEditCommitMutation, variables, nil)
+}
+
+//GetOrderEditSummary returns the line items added and removed by the changes staged on a calculated order
+//along with the resulting totals, so that the edit can be previewed before CommitOrderEdit
+func (shop *Shopify) GetOrderEditSummary(calculatedOrderID string) (*OrderEditSummary, []error) {
+	type shopMoney struct {
+		ShopMoney Money `json:"shopMoney"`
+	}
+	type lineItems struct {
+		Edges []struct {
+			Node struct {
+				ID                            string `json:"id"`
+				Title                         string `json:"title"`
+				SKU                           string `json:"sku"`
+				Quantity                      int    `json:"quantity"`
+				EditableQuantityBeforeChanges int    `json:"editableQuantityBeforeChanges"`
+			} `json:"node"`
+		} `json:"edges"`
+	}
+	var data struct {
+		Node *struct {
+			OriginalOrder struct {
+				TotalPriceSet shopMoney `json:"totalPriceSet"`
+			} `json:"originalOrder"`
+			TotalPriceSet       shopMoney `json:"totalPriceSet"`
+			TotalOutstandingSet shopMoney `json:"totalOutstandingSet"`
+			AddedLineItems      lineItems `json:"addedLineItems"`
+			LineItems           lineItems `json:"lineItems"`
+		} `json:"node"`
+	}
+	if errs := shop.graphQL(calculatedOrderQuery, map[string]interface{}{"id": calculatedOrderID}, &data); len(errs) > 0 {
+		return nil, errs
+	}
+	if data.Node == nil {
+		return nil, []error{fmt.Errorf("no calculated order found with id %v", calculatedOrderID)}
+	}
+
+	summary := &OrderEditSummary{
+		AddedLineItems:   []OrderEditLineItem{},
+		RemovedLineItems: []OrderEditLineItem{},
+		OriginalTotal:    data.Node.OriginalOrder.TotalPriceSet.ShopMoney,
+		NewTotal:         data.Node.TotalPriceSet.ShopMoney,
+		TotalOutstanding: data.Node.TotalOutstandingSet.ShopMoney,
+	}
+	for _, edge := range data.Node.AddedLineItems.Edges {
+		item := edge.Node
+		summary.AddedLineItems = append(summary.AddedLineItems, OrderEditLineItem{ID: item.ID, Title: item.Title, SKU: item.SKU, Quantity: item.Quantity})
+	}
+	for _, edge := range data.Node.LineItems.Edges {
+		item := edge.Node
+		if removed := item.EditableQuantityBeforeChanges - item.Quantity; removed > 0 {
+			summary.RemovedLineItems = append(summary.RemovedLineItems, OrderEditLineItem{ID: item.ID, Title: item.Title, SKU: item.SKU, Quantity: removed})
+		}
+	}
+	original, okOriginal := new(big.Rat).SetString(summary.OriginalTotal.Amount)
+	total, okTotal := new(big.Rat).SetString(summary.NewTotal.Amount)
+	if okOriginal && okTotal {
+		summary.TotalDifference = Money{
+			Amount:       new(big.Rat).Sub(total, original).FloatString(2),
+			CurrencyCode: summary.NewTotal.CurrencyCode,
+		}
+	}
+	return summary, nil
+}
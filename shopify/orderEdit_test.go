@@ -0,0 +1,105 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// orderEditHandler answers each order edit mutation with its mocked response, checking its variables
+func orderEditHandler(t *testing.T, mutations *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/api/graphql.json", r.URL.Path)
+		var body struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case strings.Contains(body.Query, "orderEditBegin"):
+			*mutations = append(*mutations, "begin")
+			assert.Equal(t, "gid://shopify/Order/450789469", body.Variables["id"])
+			w.Write([]byte(`{"data": {"orderEditBegin": {"calculatedOrder": {"id": "gid://shopify/CalculatedOrder/607673083"}, "userErrors": []}}}`))
+		case strings.Contains(body.Query, "orderEditAddVariant"):
+			*mutations = append(*mutations, "add")
+			assert.Equal(t, "gid://shopify/CalculatedOrder/607673083", body.Variables["id"])
+			assert.Equal(t, "gid://shopify/ProductVariant/49148385", body.Variables["variantId"])
+			assert.Equal(t, float64(2), body.Variables["quantity"])
+			w.Write([]byte(`{"data": {"orderEditAddVariant": {"calculatedLineItem": {"id": "gid://shopify/CalculatedLineItem/1"}, "userErrors": []}}}`))
+		case strings.Contains(body.Query, "orderEditCommit"):
+			*mutations = append(*mutations, "commit")
+			assert.Equal(t, true, body.Variables["notifyCustomer"])
+			w.Write([]byte(`{"data": {"orderEditCommit": {"order": {"id": "gid://shopify/Order/450789469"}, "userErrors": []}}}`))
+		default:
+			t.Errorf("unexpected query %v", body.Query)
+		}
+	}
+}
+
+// Should begin an edit, add a variant to the calculated order and commit it
+func TestOrderEdit(t *testing.T) {
+	var mutations []string
+	mock, server := newMockShopify(t, orderEditHandler(t, &mutations))
+	defer server.Close()
+
+	calculatedOrderID, errs := mock.BeginOrderEdit(450789469)
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "gid://shopify/CalculatedOrder/607673083", calculatedOrderID)
+
+	errs = mock.OrderEditAddVariant(calculatedOrderID, "gid://shopify/ProductVariant/49148385", 2)
+	assert.T(t, errs == nil, errs)
+
+	errs = mock.CommitOrderEdit(calculatedOrderID, true)
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"begin", "add", "commit"}, mutations)
+}
+
+// Should report the user errors and the GraphQL errors of the mutations
+func TestOrderEditErrors(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if strings.Contains(body.Query, "orderEditBegin") {
+			w.Write([]byte(`{"data": {"orderEditBegin": {"calculatedOrder": null, "userErrors": [{"field": ["id"], "message": "The order cannot be edited"}]}}}`))
+			return
+		}
+		w.Write([]byte(`{"errors": [{"message": "Variable $variantId of type ID! was provided invalid value"}]}`))
+	})
+	defer server.Close()
+
+	_, errs := mock.BeginOrderEdit(450789469)
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, ErrorMessages{"id": {"The order cannot be edited"}}, findShopifyError(errs).Errors)
+
+	errs = mock.OrderEditAddVariant("gid://shopify/CalculatedOrder/607673083", "49148385", 1)
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, ErrorMessages{"base": {"Variable $variantId of type ID! was provided invalid value"}}, findShopifyError(errs).Errors)
+}
+
+// Should list the added and removed line items and the change of total of the calculated order
+func TestGetOrderEditSummary(t *testing.T) {
+	fixture := loadFixture(t, "graphql_calculated_order.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "gid://shopify/CalculatedOrder/607673083", body.Variables["id"])
+		w.Write(fixture)
+	})
+	defer server.Close()
+
+	summary, errs := mock.GetOrderEditSummary("gid://shopify/CalculatedOrder/607673083")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []OrderEditLineItem{{ID: "gid://shopify/CalculatedLineItem/1", Title: "IPod Shuffle", SKU: "SHUFFLE", Quantity: 1}}, summary.AddedLineItems)
+	assert.Equal(t, []OrderEditLineItem{{ID: "gid://shopify/CalculatedLineItem/466157049", Title: "IPod Nano - 8gb", SKU: "IPOD2008GREEN", Quantity: 1}}, summary.RemovedLineItems)
+	assert.Equal(t, Money{Amount: "409.94", CurrencyCode: "USD"}, summary.OriginalTotal)
+	assert.Equal(t, Money{Amount: "-189.00", CurrencyCode: "USD"}, summary.TotalDifference)
+	assert.Equal(t, "-189.0", summary.TotalOutstanding.Amount)
+}
@@ -0,0 +1,151 @@
+package shopify
+
+import (
+	"fmt"
+	"strings"
+)
+
+const tagsAddMutation = `mutation($id: ID!, $tags: [String!]!) {
+  tagsAdd(id: $id, tags: $tags) {
+    node { id }
+    userErrors { field message }
+  }
+}`
+
+const tagsRemoveMutation = `mutation($id: ID!, $tags: [String!]!) {
+  tagsRemove(id: $id, tags: $tags) {
+    node { id }
+    userErrors { field message }
+  }
+}`
+
+//AddOrderTags adds the given tags to the ones the order already has, skipping duplicates
+func (shop *Shopify) AddOrderTags(orderID int64, tags []string) (*Order, []error) {
+	order, errs := shop.GetOrder(orderID)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return shop.updateOrder(orderID, map[string]interface{}{
+		"tags": joinTags(mergeTags(splitTags(order.Tags), tags)),
+	})
+}
+
+//AddOrderTagsAtomic adds the given tags to the order, given by its global id e.g. GlobalID("Order", orderID).
+//Unlike AddOrderTags shopify merges the tags itself, so concurrent edits of the tags don't overwrite each other.
+func (shop *Shopify) AddOrderTagsAtomic(orderGID string, tags []string) []error {
+	return shop.mutateOrderTags(tagsAddMutation, orderGID, tags)
+}
+
+//RemoveOrderTagsAtomic removes the given tags from the order, given by its global id, in a single mutation
+func (shop *Shopify) RemoveOrderTagsAtomic(orderGID string, tags []string) []error {
+	return shop.mutateOrderTags(tagsRemoveMutation, orderGID, tags)
+}
+
+// mutateOrderTags runs a tagsAdd or tagsRemove mutation on an order, doing nothing without tags
+func (shop *Shopify) mutateOrderTags(mutation, orderGID string, tags []string) []error {
+	if !strings.HasPrefix(orderGID, "gid://shopify/Order/") {
+		return []error{fmt.Errorf("invalid order global id %q", orderGID)}
+	}
+	tags = mergeTags(nil, tags)
+	if len(tags) == 0 {
+		return nil
+	}
+	return shop.graphQL(mutation, map[string]interface{}{"id": orderGID, "tags": tags}, nil)
+}
+
+//SetOrderNote replaces the order's note
+func (shop *Shopify) SetOrderNote(orderID int64, note string) (*Order, []error) {
+	return shop.updateOrder(orderID, map[string]interface{}{"note": note})
+}
+
+//GetOrderAttributes returns the order's note attributes, e.g. the custom fields of the checkout, keyed by
+//name. When several attributes have the same name the last one wins, as it does on the order page.
+func (shop *Shopify) GetOrderAttributes(orderID int64) (map[string]string, []error) {
+	order, errs := shop.GetOrder(orderID)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	attributes := make(map[string]string)
+	if order.NoteAttributes != nil {
+		for _, attribute := range *order.NoteAttributes {
+			attributes[attribute.Name] = attribute.Value
+		}
+	}
+	return attributes, nil
+}
+
+//SetOrderAttribute sets the value of the order's note attribute with the given name, adding it when there is
+//none. The other attributes keep their order and the duplicates of the name are dropped.
+func (shop *Shopify) SetOrderAttribute(orderID int64, name, value string) (*Order, []error) {
+	order, errs := shop.GetOrder(orderID)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	attributes := []NoteAttribute{}
+	found := false
+	if order.NoteAttributes != nil {
+		for _, attribute := range *order.NoteAttributes {
+			if attribute.Name != name {
+				attributes = append(attributes, attribute)
+			} else if !found {
+				found = true
+				attributes = append(attributes, NoteAttribute{Name: name, Value: value})
+			}
+		}
+	}
+	if !found {
+		attributes = append(attributes, NoteAttribute{Name: name, Value: value})
+	}
+	return shop.updateOrder(orderID, map[string]interface{}{"note_attributes": attributes})
+}
+
+//UpdateOrderShippingAddress changes the non blank fields of the order's shipping address
+func (shop *Shopify) UpdateOrderShippingAddress(orderID int64, address Address) (*Order, []error) {
+	return shop.updateOrder(orderID, map[string]interface{}{"shipping_address": address})
+}
+
+//UpdateOrderCustomer assigns the order to another customer
+func (shop *Shopify) UpdateOrderCustomer(orderID, customerID int64) (*Order, []error) {
+	return shop.updateOrder(orderID, map[string]interface{}{"customer": map[string]interface{}{"id": customerID}})
+}
+
+// updateOrder PUTs the given fields of an order
+func (shop *Shopify) updateOrder(orderID int64, fields map[string]interface{}) (*Order, []error) {
+	var orderResponse OrderResponse
+	fields["id"] = orderID
+	response, errors := shop.Put(fmt.Sprintf("orders/%v", orderID), map[string]interface{}{"order": fields})
+	if err := unmarshal(response, errors, &orderResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &orderResponse.Order, nil
+}
+
+// splitTags splits shopify's comma joined tags, dropping the blank ones
+func splitTags(tags string) []string {
+	var split []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			split = append(split, tag)
+		}
+	}
+	return split
+}
+
+// mergeTags appends the new tags to the existing ones keeping the first occurrence of each
+func mergeTags(existing, tags []string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, tag := range append(existing, tags...) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		merged = append(merged, tag)
+	}
+	return merged
+}
+
+func joinTags(tags []string) string {
+	return strings.Join(tags, ", ")
+}
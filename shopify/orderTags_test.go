@@ -0,0 +1,186 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// orderUpdateHandler serves the order fixture and hands the decoded PUT body to check
+func orderUpdateHandler(t *testing.T, check func(order map[string]interface{})) http.HandlerFunc {
+	fixture := loadFixture(t, "order.json")
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/orders/450789469.json", r.URL.Path)
+		if r.Method == "PUT" {
+			var body map[string]map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			check(body["order"])
+		}
+		w.Write(fixture)
+	}
+}
+
+// Should merge the new tags after the existing ones without duplicates
+func TestAddOrderTags(t *testing.T) {
+	puts := 0
+	mock, server := newMockShopify(t, orderUpdateHandler(t, func(order map[string]interface{}) {
+		puts++
+		assert.Equal(t, float64(450789469), order["id"])
+		assert.Equal(t, "imported, vip, priority, wholesale", order["tags"])
+	}))
+	defer server.Close()
+
+	_, errs := mock.AddOrderTags(450789469, []string{"priority", " vip", "wholesale", "priority", ""})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 1, puts)
+}
+
+// Should replace the order note
+func TestSetOrderNote(t *testing.T) {
+	puts := 0
+	mock, server := newMockShopify(t, orderUpdateHandler(t, func(order map[string]interface{}) {
+		puts++
+		assert.Equal(t, "Customer asked for gift wrapping", order["note"])
+	}))
+	defer server.Close()
+
+	_, errs := mock.SetOrderNote(450789469, "Customer asked for gift wrapping")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 1, puts)
+}
+
+// Should flatten the note attributes, the last of a repeated name winning
+func TestGetOrderAttributes(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/orders/450789469.json", "order.json"))
+	defer server.Close()
+
+	attributes, errs := mock.GetOrderAttributes(450789469)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, map[string]string{"gift_wrap": "no", "delivery_date": "2008-01-14"}, attributes)
+}
+
+// Should replace the attribute in place dropping its duplicates, or append it when missing
+func TestSetOrderAttribute(t *testing.T) {
+	var written []interface{}
+	mock, server := newMockShopify(t, orderUpdateHandler(t, func(order map[string]interface{}) {
+		written = append(written, order["note_attributes"])
+	}))
+	defer server.Close()
+
+	_, errs := mock.SetOrderAttribute(450789469, "gift_wrap", "maybe")
+	assert.T(t, errs == nil, errs)
+	_, errs = mock.SetOrderAttribute(450789469, "pickup_point", "Ottawa")
+	assert.T(t, errs == nil, errs)
+
+	assert.Equal(t, []interface{}{
+		[]interface{}{
+			map[string]interface{}{"name": "gift_wrap", "value": "maybe"},
+			map[string]interface{}{"name": "delivery_date", "value": "2008-01-14"},
+		},
+		[]interface{}{
+			map[string]interface{}{"name": "gift_wrap", "value": "yes"},
+			map[string]interface{}{"name": "delivery_date", "value": "2008-01-14"},
+			map[string]interface{}{"name": "gift_wrap", "value": "no"},
+			map[string]interface{}{"name": "pickup_point", "value": "Ottawa"},
+		},
+	}, written)
+}
+
+// Should PUT only the given fields of the shipping address
+func TestUpdateOrderShippingAddress(t *testing.T) {
+	puts := 0
+	mock, server := newMockShopify(t, orderUpdateHandler(t, func(order map[string]interface{}) {
+		puts++
+		assert.Equal(t, map[string]interface{}{
+			"address1": "123 Amoebobacterieae St",
+			"city":     "Ottawa",
+			"province": "Ontario",
+			"country":  "Canada",
+			"zip":      "K2P0V6",
+			"phone":    "555-555-5555",
+		}, order["shipping_address"])
+	}))
+	defer server.Close()
+
+	_, errs := mock.UpdateOrderShippingAddress(450789469, Address{
+		Address1: "123 Amoebobacterieae St",
+		City:     "Ottawa",
+		Province: "Ontario",
+		Country:  "Canada",
+		Zip:      "K2P0V6",
+		Phone:    "555-555-5555",
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 1, puts)
+}
+
+// Should PUT the id of the new customer
+func TestUpdateOrderCustomer(t *testing.T) {
+	puts := 0
+	mock, server := newMockShopify(t, orderUpdateHandler(t, func(order map[string]interface{}) {
+		puts++
+		assert.Equal(t, map[string]interface{}{"id": float64(207119551)}, order["customer"])
+	}))
+	defer server.Close()
+
+	_, errs := mock.UpdateOrderCustomer(450789469, 207119551)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 1, puts)
+}
+
+func TestSplitTags(t *testing.T) {
+	assert.Equal(t, []string{"a", "b c", "d"}, splitTags(" a,b c,, d ,"))
+	assert.T(t, splitTags("") == nil)
+}
+
+// Should add and remove the trimmed tags through the tagsAdd and tagsRemove mutations
+func TestOrderTagsAtomic(t *testing.T) {
+	var bodies []map[string]interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/api/graphql.json", r.URL.Path)
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Write([]byte(`{"data": {"tags": {"node": {"id": "gid://shopify/Order/450789469"}, "userErrors": []}}}`))
+	})
+	defer server.Close()
+
+	orderGID := GlobalID("Order", 450789469)
+	assert.Equal(t, "gid://shopify/Order/450789469", orderGID)
+
+	errs := mock.AddOrderTagsAtomic(orderGID, []string{" vip", "wholesale", "vip", ""})
+	assert.T(t, errs == nil, errs)
+	errs = mock.RemoveOrderTagsAtomic(orderGID, []string{"to-review"})
+	assert.T(t, errs == nil, errs)
+	errs = mock.RemoveOrderTagsAtomic(orderGID, []string{" "})
+	assert.T(t, errs == nil, errs)
+
+	assert.Equal(t, 2, len(bodies))
+	assert.T(t, strings.Contains(bodies[0]["query"].(string), "tagsAdd("))
+	assert.Equal(t, map[string]interface{}{"id": orderGID, "tags": []interface{}{"vip", "wholesale"}}, bodies[0]["variables"])
+	assert.T(t, strings.Contains(bodies[1]["query"].(string), "tagsRemove("))
+	assert.Equal(t, map[string]interface{}{"id": orderGID, "tags": []interface{}{"to-review"}}, bodies[1]["variables"])
+}
+
+// Should surface the userErrors of the mutation and refuse ids that aren't an order's
+func TestOrderTagsAtomicErrors(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"tagsAdd": {"node": null, "userErrors": [{"field": ["id"], "message": "Order does not exist"}]}}}`))
+	})
+	defer server.Close()
+
+	errs := mock.AddOrderTagsAtomic(GlobalID("Order", 1), []string{"vip"})
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, ErrorMessages{"id": {"Order does not exist"}}, findShopifyError(errs).Errors)
+
+	errs = mock.AddOrderTagsAtomic("450789469", []string{"vip"})
+	assert.Equal(t, `invalid order global id "450789469"`, errs[0].Error())
+}
@@ -0,0 +1,83 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const orderEventsQuery = `query($id: ID!, $after: String) {
+  order(id: $id) {
+    events(first: 250, after: $after, sortKey: CREATED_AT) {
+      pageInfo { hasNextPage endCursor }
+      edges {
+        node {
+          __typename
+          id
+          message
+          createdAt
+          ... on CommentEvent { author { name } }
+        }
+      }
+    }
+  }
+}`
+
+//TimelineEvent is an entry of an order's timeline, either a comment left by the staff or an event
+//recorded by shopify or an app, e.g. "Order was paid"
+type TimelineEvent struct {
+	ID        string
+	Message   string
+	CreatedAt ShopTime
+	// StaffComment tells whether the event is a comment written by a staff member
+	StaffComment bool
+	// Author is the name of the staff member who wrote the comment, empty for the other events
+	Author string
+}
+
+//GetOrderTimeline returns the events of the order's timeline, oldest first
+func (shop *Shopify) GetOrderTimeline(orderID int64) ([]TimelineEvent, []error) {
+	found := false
+	events := connectionAt("order", "events")
+	extract := func(data []byte) ([]json.RawMessage, bool, string) {
+		var page struct {
+			Order json.RawMessage `json:"order"`
+		}
+		json.Unmarshal(data, &page)
+		found = len(page.Order) > 0 && string(page.Order) != "null"
+		return events(data)
+	}
+	variables := map[string]interface{}{"id": GlobalID("Order", orderID)}
+	nodes, errs := shop.PaginateConnection(orderEventsQuery, variables, extract)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if !found {
+		return nil, []error{fmt.Errorf("order %v not found", orderID)}
+	}
+	var timeline []TimelineEvent
+	for _, node := range nodes {
+		var event struct {
+			Typename  string   `json:"__typename"`
+			ID        string   `json:"id"`
+			Message   string   `json:"message"`
+			CreatedAt ShopTime `json:"createdAt"`
+			Author    *struct {
+				Name string `json:"name"`
+			} `json:"author"`
+		}
+		if err := json.Unmarshal(node, &event); err != nil {
+			return nil, []error{err}
+		}
+		timelineEvent := TimelineEvent{
+			ID:           event.ID,
+			Message:      event.Message,
+			CreatedAt:    event.CreatedAt,
+			StaffComment: event.Typename == "CommentEvent",
+		}
+		if event.Author != nil {
+			timelineEvent.Author = event.Author.Name
+		}
+		timeline = append(timeline, timelineEvent)
+	}
+	return timeline, nil
+}
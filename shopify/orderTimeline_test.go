@@ -0,0 +1,54 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should page through the order's events and flag the staff comments
+func TestGetOrderTimeline(t *testing.T) {
+	var cursors []interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "gid://shopify/Order/450789469", body["variables"]["id"])
+		cursors = append(cursors, body["variables"]["after"])
+		if body["variables"]["after"] == nil {
+			w.Write(loadFixture(t, "graphql_order_events_page_1.json"))
+			return
+		}
+		w.Write(loadFixture(t, "graphql_order_events_page_2.json"))
+	})
+	defer server.Close()
+
+	events, errs := mock.GetOrderTimeline(450789469)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []interface{}{nil, "eyJsYXN0X2lkIjoxMDAwMDAwMDAyfQ"}, cursors)
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, "Order was placed from the Online Store.", events[0].Message)
+	assert.Equal(t, time.Date(2021, 6, 9, 19, 20, 44, 0, time.UTC), events[0].CreatedAt.UTC())
+	assert.T(t, !events[0].StaffComment)
+	assert.Equal(t, "", events[0].Author)
+	assert.Equal(t, "gid://shopify/CommentEvent/1000000003", events[2].ID)
+	assert.T(t, events[2].StaffComment)
+	assert.Equal(t, "Bob Norman", events[2].Author)
+}
+
+// Should report an order that doesn't exist
+func TestGetOrderTimelineNotFound(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"order": null}}`))
+	})
+	defer server.Close()
+
+	events, errs := mock.GetOrderTimeline(1)
+
+	assert.Equal(t, 0, len(events))
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, "order 1 not found", errs[0].Error())
+}
@@ -0,0 +1,409 @@
+package shopify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should decode the orders list
+func TestGetOrdersMock(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/orders.json", "orders.json"))
+	defer server.Close()
+
+	orders, errs := mock.GetOrders(nil)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(orders))
+	assert.Equal(t, int64(450789469), orders[0].ID)
+	assert.Equal(t, "#1002", orders[1].Name)
+	assert.Equal(t, 1, len(orders[0].LineItems))
+}
+
+// Should search any order by name and keep the exact match
+func TestGetOrderByName(t *testing.T) {
+	fixture := fixtureHandler(t, "/admin/orders.json", "orders.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "any", r.URL.Query().Get("status"))
+		fixture(w, r)
+	})
+	defer server.Close()
+
+	order, errs := mock.GetOrderByName("#1001")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, int64(450789469), order.ID)
+	assert.Equal(t, "#1001", order.Name)
+	assert.Equal(t, int64(1001), order.OrderNumber)
+
+	order, errs = mock.GetOrderByName("#100")
+	assert.T(t, order == nil)
+	assert.Equal(t, `no order found with name "#100"`, errs[0].Error())
+}
+
+// Should decode the code and manual discounts applied to the order
+func TestGetOrderDiscounts(t *testing.T) {
+	fixture := fixtureHandler(t, "/admin/orders/450789469.json", "order.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "id,discount_applications", r.URL.Query().Get("fields"))
+		fixture(w, r)
+	})
+	defer server.Close()
+
+	discounts, errs := mock.GetOrderDiscounts(450789469)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(discounts))
+	assert.Equal(t, DiscountApplication{
+		Type:             "discount_code",
+		Code:             "SPRINGSALE",
+		Title:            "SPRINGSALE",
+		Value:            "10.0",
+		ValueType:        "percentage",
+		AllocationMethod: "across",
+		TargetSelection:  "all",
+		TargetType:       "line_item",
+	}, discounts[0])
+	assert.Equal(t, "manual", discounts[1].Type)
+	assert.Equal(t, "", discounts[1].Code)
+	assert.Equal(t, "fixed_amount", discounts[1].ValueType)
+}
+
+// Should tell the fully, partially and not shipped line items apart, ignoring cancelled fulfillments
+func TestGetLineItemFulfillmentStatus(t *testing.T) {
+	fixture := fixtureHandler(t, "/admin/orders/450789469.json", "order_partially_fulfilled.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "id,line_items,fulfillments", r.URL.Query().Get("fields"))
+		fixture(w, r)
+	})
+	defer server.Close()
+
+	statuses, errs := mock.GetLineItemFulfillmentStatus(450789469)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, map[int64]string{
+		466157049: "fulfilled",
+		518995019: "partial",
+		703073504: "unfulfilled",
+	}, statuses)
+}
+
+// Should decode every shipping line of the order with its carrier
+func TestGetOrderShippingLines(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/orders/450789469.json", "order.json"))
+	defer server.Close()
+
+	order, errs := mock.GetOrder(450789469)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(order.ShippingLines))
+	assert.Equal(t, "Free Shipping", order.ShippingLines[0].Title)
+	assert.Equal(t, "", order.ShippingLines[0].CarrierIdentifier)
+	ups := order.ShippingLines[1]
+	assert.Equal(t, "03", ups.Code)
+	assert.Equal(t, "11.94", ups.Price.Amount)
+	assert.Equal(t, "5c3b4a4fa2cd06ed0b2e6f0bb7ae5f3d", ups.CarrierIdentifier)
+	assert.Equal(t, "0.72", ups.TaxLines[0].Price.Amount)
+}
+
+// Should nest the capture under the authorization and the refund under the capture, keeping orphans as roots
+func TestGetOrderTransactionTree(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/orders/450789469/transactions.json", "transactions_tree.json"))
+	defer server.Close()
+
+	tree, errs := mock.GetOrderTransactionTree(450789469)
+
+	assert.T(t, errs == nil, errs)
+	var roots []string
+	for _, node := range tree {
+		roots = append(roots, node.Transaction.Kind)
+	}
+	assert.Equal(t, []string{"authorization", "refund", "sale"}, roots)
+
+	authorization := tree[0]
+	assert.Equal(t, 1, len(authorization.Children))
+	capture := authorization.Children[0]
+	assert.Equal(t, "capture", capture.Transaction.Kind)
+	assert.Equal(t, 1, len(capture.Children))
+	assert.Equal(t, int64(389404471), capture.Children[0].Transaction.ID)
+	assert.Equal(t, 0, len(capture.Children[0].Children))
+
+	assert.Equal(t, int64(389404472), tree[1].Transaction.ID)
+	assert.Equal(t, 0, len(tree[1].Children))
+}
+
+// Should dedup the payment gateway names of the order
+func TestGetOrderGateways(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/orders/450789469.json", "order.json"))
+	defer server.Close()
+
+	gateways, errs := mock.GetOrderGateways(450789469)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"gift_card", "bogus"}, gateways)
+}
+
+// Should fall back to the gateways of the transactions
+func TestGetOrderGatewaysFromTransactions(t *testing.T) {
+	transactions := fixtureHandler(t, "/admin/orders/450789469/transactions.json", "transactions.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/orders/450789469.json" {
+			w.Write([]byte(`{"order": {"id": 450789469, "payment_gateway_names": []}}`))
+			return
+		}
+		transactions(w, r)
+	})
+	defer server.Close()
+
+	gateways, errs := mock.GetOrderGateways(450789469)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"bogus"}, gateways)
+}
+
+// pagedOrdersHandler serves orders.json as the first page and orders_page_2.json as the last one
+func pagedOrdersHandler(t *testing.T, pages *int) http.HandlerFunc {
+	first := loadFixture(t, "orders.json")
+	second := loadFixture(t, "orders_page_2.json")
+	return func(w http.ResponseWriter, r *http.Request) {
+		*pages++
+		assert.Equal(t, "/admin/orders.json", r.URL.Path)
+		switch r.URL.Query().Get("page_info") {
+		case "":
+			assert.Equal(t, "paid", r.URL.Query().Get("financial_status"))
+			w.Header().Set("Link", `<https://mock.myshopify.com/admin/orders.json?page_info=cGFnZTI&limit=2>; rel="next"`)
+			w.Write(first)
+		case "cGFnZTI":
+			assert.Equal(t, "2", r.URL.Query().Get("limit"))
+			assert.Equal(t, "", r.URL.Query().Get("financial_status"))
+			w.Header().Set("Link", `<https://mock.myshopify.com/admin/orders.json?page_info=cGFnZTE&limit=2>; rel="previous"`)
+			w.Write(second)
+		default:
+			t.Errorf("unexpected page_info %v", r.URL.Query().Get("page_info"))
+		}
+	}
+}
+
+// Should only return the orders of every page with a line item of the variant
+func TestGetOrdersContainingVariant(t *testing.T) {
+	first := loadFixture(t, "orders.json")
+	second := loadFixture(t, "orders_page_2.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page_info") == "" {
+			assert.Equal(t, "2008-01-01T00:00:00Z", r.URL.Query().Get("created_at_min"))
+			w.Header().Set("Link", `<https://mock.myshopify.com/admin/orders.json?page_info=cGFnZTI&limit=2>; rel="next"`)
+			w.Write(first)
+			return
+		}
+		w.Write(second)
+	})
+	defer server.Close()
+
+	orders, errs := mock.GetOrdersContainingVariant(39072856, map[string]string{"created_at_min": "2008-01-01T00:00:00Z", "limit": "2"})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(orders))
+	assert.Equal(t, int64(450789469), orders[0].ID)
+	assert.Equal(t, int64(450789471), orders[1].ID)
+}
+
+// Should call fn for every order across pages
+func TestStreamOrders(t *testing.T) {
+	pages := 0
+	mock, server := newMockShopify(t, pagedOrdersHandler(t, &pages))
+	defer server.Close()
+
+	var ids []int64
+	errs := mock.StreamOrders(map[string]string{"financial_status": "paid", "limit": "2"}, func(order Order) error {
+		ids = append(ids, order.ID)
+		return nil
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, pages)
+	assert.Equal(t, []int64{450789469, 450789470, 450789471}, ids)
+}
+
+// Should stop at the first error returned by fn
+func TestStreamOrdersCallbackError(t *testing.T) {
+	pages := 0
+	mock, server := newMockShopify(t, pagedOrdersHandler(t, &pages))
+	defer server.Close()
+
+	stop := errors.New("stop")
+	calls := 0
+	errs := mock.StreamOrders(map[string]string{"financial_status": "paid", "limit": "2"}, func(order Order) error {
+		calls++
+		return stop
+	})
+
+	assert.Equal(t, []error{stop}, errs)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, pages)
+}
+
+// Should not fetch the next page once the context is cancelled
+func TestStreamOrdersContextCancelled(t *testing.T) {
+	pages := 0
+	mock, server := newMockShopify(t, pagedOrdersHandler(t, &pages))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := mock.WithContext(ctx).StreamOrders(map[string]string{"financial_status": "paid", "limit": "2"}, func(order Order) error {
+		cancel()
+		return nil
+	})
+
+	assert.Equal(t, []error{context.Canceled}, errs)
+	assert.Equal(t, 1, pages)
+}
+
+// Should fetch every order's transactions and isolate the one that fails
+func TestGetTransactionsForOrders(t *testing.T) {
+	fixture := loadFixture(t, "transactions.json")
+	var mu sync.Mutex
+	var paths []string
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		if strings.HasPrefix(r.URL.Path, "/admin/orders/3/") {
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors":"Not Found"}`))
+			return
+		}
+		w.Write(fixture)
+	})
+	defer server.Close()
+
+	transactions, errs := mock.GetTransactionsForOrders([]int64{1, 2, 3})
+
+	assert.Equal(t, 3, len(paths))
+	assert.Equal(t, 1, len(errs))
+	assert.T(t, strings.HasPrefix(errs[0].Error(), "order 3: "), errs[0])
+	var shopifyError *ShopifyError
+	assert.T(t, errors.As(errs[0], &shopifyError))
+	assert.Equal(t, 404, shopifyError.StatusCode)
+
+	assert.Equal(t, 2, len(transactions))
+	assert.Equal(t, int64(389404469), transactions[1][0].ID)
+	assert.Equal(t, "409.94", transactions[2][0].Amount.Amount)
+	_, found := transactions[3]
+	assert.T(t, !found)
+}
+
+// Should count the orders of each financial status
+func TestCountOrdersByFinancialStatus(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		assert.Equal(t, "/admin/orders/count.json", r.URL.Path)
+		assert.Equal(t, "any", r.URL.Query().Get("status"))
+		counts := map[string]string{"paid": "12", "pending": "3", "refunded": "1"}
+		w.Write([]byte(`{"count": ` + counts[r.URL.Query().Get("financial_status")] + `}`))
+	})
+	defer server.Close()
+
+	counts, errs := mock.CountOrdersByFinancialStatus([]string{"paid", "pending", "refunded"})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, map[string]int{"paid": 12, "pending": 3, "refunded": 1}, counts)
+}
+
+// Should count the orders created in the range using the store's timezone
+func TestCountOrdersByDateRange(t *testing.T) {
+	shopFixture := fixtureHandler(t, "/admin/shop.json", "shop.json")
+	counts := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/shop.json" {
+			shopFixture(w, r)
+			return
+		}
+		counts++
+		assert.Equal(t, "/admin/orders/count.json", r.URL.Path)
+		query := r.URL.Query()
+		assert.Equal(t, "any", query.Get("status"))
+		assert.Equal(t, "2018-01-01T00:00:00-05:00", query.Get("created_at_min"))
+		assert.Equal(t, "2018-01-02T00:00:00-05:00", query.Get("created_at_max"))
+		w.Write([]byte(`{"count": 27}`))
+	})
+	defer server.Close()
+
+	from := time.Date(2018, 1, 1, 5, 0, 0, 0, time.UTC)
+	count, errs := mock.CountOrdersByDateRange(from, from.Add(24*time.Hour))
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 27, count)
+	assert.Equal(t, 1, counts)
+}
+
+// Should share the store's timezone between concurrent counts, also through copies bound to a context
+func TestCountOrdersByDateRangeConcurrent(t *testing.T) {
+	shopFixture := fixtureHandler(t, "/admin/shop.json", "shop.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/shop.json" {
+			shopFixture(w, r)
+			return
+		}
+		assert.Equal(t, "2018-01-01T00:00:00-05:00", r.URL.Query().Get("created_at_min"))
+		w.Write([]byte(`{"count": 27}`))
+	})
+	defer server.Close()
+
+	from := time.Date(2018, 1, 1, 5, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count, errs := mock.WithContext(context.Background()).CountOrdersByDateRange(from, from.Add(24*time.Hour))
+			assert.T(t, errs == nil, errs)
+			assert.Equal(t, 27, count)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, "America/New_York", mock.cachedTimezone().String())
+}
+
+// Should fall back to UTC for a store without a timezone and fetch the shop only once
+func TestCountOrdersByDateRangeWithoutTimezone(t *testing.T) {
+	shopCalls := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/shop.json" {
+			shopCalls++
+			w.Write([]byte(`{"shop": {"id": 690933842, "name": "Apple Computers", "iana_timezone": ""}}`))
+			return
+		}
+		assert.Equal(t, "2018-01-01T05:00:00Z", r.URL.Query().Get("created_at_min"))
+		w.Write([]byte(`{"count": 27}`))
+	})
+	defer server.Close()
+
+	from := time.Date(2018, 1, 1, 5, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		count, errs := mock.CountOrdersByDateRange(from, from.Add(24*time.Hour))
+		assert.T(t, errs == nil, errs)
+		assert.Equal(t, 27, count)
+	}
+	assert.Equal(t, 1, shopCalls)
+}
+
+// Should reject a range that ends before it starts
+func TestCountOrdersByDateRangeInvalid(t *testing.T) {
+	from := time.Date(2018, 1, 2, 0, 0, 0, 0, time.UTC)
+	count, errs := shop.CountOrdersByDateRange(from, from.Add(-time.Hour))
+
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 1, len(errs))
+}
@@ -0,0 +1,131 @@
+package shopify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// errEnoughItems stops GetFirstN's pagination once it collected the items it needs
+var errEnoughItems = errors.New("enough items")
+
+// GetFirstN Returns the first n items of a list endpoint, following its pages only until n items are
+// collected and trimming the last page, e.g. to sync the latest orders without walking the whole list.
+// The body has the same shape as a page, e.g. {"orders": [...]}, with at most n items.
+// Usage: shopify.GetFirstN("orders", 500, map[string]string{"status": "any"})
+func (shopify *Shopify) GetFirstN(endpoint string, n int, parameters map[string]string) ([]byte, []error) {
+	if n < 1 {
+		return nil, []error{fmt.Errorf("invalid count %d, it must be positive", n)}
+	}
+	pageParameters := make(map[string]string, len(parameters)+1)
+	for key, value := range parameters {
+		pageParameters[key] = value
+	}
+	limit := shopify.maxLimit(endpoint)
+	if n < limit {
+		limit = n
+	}
+	pageParameters["limit"] = strconv.Itoa(limit)
+
+	key := endpoint[strings.LastIndex(endpoint, "/")+1:]
+	var items []json.RawMessage
+	errs := shopify.paginate(endpoint, pageParameters, func(page []byte) []error {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(page, &fields); err != nil {
+			return []error{err}
+		}
+		if _, ok := fields[key]; !ok && len(fields) == 1 {
+			// the items of e.g. customers/search are under customers
+			for field := range fields {
+				key = field
+			}
+		}
+		var pageItems []json.RawMessage
+		if err := json.Unmarshal(fields[key], &pageItems); err != nil {
+			return []error{err}
+		}
+		items = append(items, pageItems...)
+		if len(items) >= n {
+			return []error{errEnoughItems}
+		}
+		return nil
+	})
+	if len(errs) > 0 && errs[0] != errEnoughItems {
+		return nil, errs
+	}
+	if len(items) > n {
+		items = items[:n]
+	}
+	if items == nil {
+		items = []json.RawMessage{}
+	}
+	body, err := json.Marshal(map[string]interface{}{key: items})
+	if err != nil {
+		return nil, []error{err}
+	}
+	return body, nil
+}
+
+// paginate walks every page of endpoint following the cursors in the Link header,
+// handing each page body to fn. It stops on the first error returned by shopify or
+// fn, or once the context is done.
+func (shopify *Shopify) paginate(endpoint string, parameters map[string]string, fn func(page []byte) []error) []error {
+	if err := shopify.checkLimit(endpoint, parameters); err != nil {
+		return []error{err}
+	}
+	ctx := shopify.context()
+	for {
+		if err := ctx.Err(); err != nil {
+			return []error{err}
+		}
+		response, body, errs := shopify.send("GET", shopify.createTargetURLWithParameters(endpoint, parameters), nil)
+		if len(errs) > 0 {
+			return errs
+		}
+		if errs := fn(body); len(errs) > 0 {
+			return errs
+		}
+		next, _ := ParseLinkHeader(response.Header.Get("Link"))
+		if next == "" {
+			return nil
+		}
+		parameters = nextPageParameters(parameters, next)
+	}
+}
+
+// nextPageParameters keeps only the parameters shopify accepts alongside a page_info cursor
+func nextPageParameters(parameters map[string]string, pageInfo string) map[string]string {
+	next := map[string]string{"page_info": pageInfo}
+	for _, key := range []string{"limit", "fields"} {
+		if value, ok := parameters[key]; ok {
+			next[key] = value
+		}
+	}
+	return next
+}
+
+// ParseLinkHeader extracts the next and previous page_info cursors from a Link header value like
+// <https://store.myshopify.com/admin/products.json?page_info=abc&limit=50>; rel="next"
+func ParseLinkHeader(header string) (next, prev string) {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		target := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+		linkURL, err := url.Parse(target)
+		if err != nil {
+			continue
+		}
+		pageInfo := linkURL.Query().Get("page_info")
+		for _, attribute := range parts[1:] {
+			switch strings.TrimSpace(attribute) {
+			case `rel="next"`:
+				next = pageInfo
+			case `rel="previous"`:
+				prev = pageInfo
+			}
+		}
+	}
+	return next, prev
+}
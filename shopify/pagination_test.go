@@ -0,0 +1,78 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should extract the next cursor of the first page
+func TestParseLinkHeaderNext(t *testing.T) {
+	next, prev := ParseLinkHeader(`<https://apple.myshopify.com/admin/products.json?limit=50&page_info=bmV4dA>; rel="next"`)
+
+	assert.Equal(t, "bmV4dA", next)
+	assert.Equal(t, "", prev)
+}
+
+// Should extract the previous cursor of the last page
+func TestParseLinkHeaderPrevious(t *testing.T) {
+	next, prev := ParseLinkHeader(`<https://apple.myshopify.com/admin/products.json?limit=50&page_info=cHJldg>; rel="previous"`)
+
+	assert.Equal(t, "", next)
+	assert.Equal(t, "cHJldg", prev)
+}
+
+// Should extract both cursors of a middle page, in any order
+func TestParseLinkHeaderBoth(t *testing.T) {
+	next, prev := ParseLinkHeader(`<https://apple.myshopify.com/admin/products.json?limit=50&page_info=cHJldg>; rel="previous", ` +
+		`<https://apple.myshopify.com/admin/products.json?limit=50&page_info=bmV4dA>; rel="next"`)
+
+	assert.Equal(t, "bmV4dA", next)
+	assert.Equal(t, "cHJldg", prev)
+
+	next, prev = ParseLinkHeader("")
+	assert.Equal(t, "", next)
+	assert.Equal(t, "", prev)
+}
+
+// Should stop paging once it has n items and trim the last page
+func TestGetFirstN(t *testing.T) {
+	var requests []string
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/orders.json", r.URL.Path)
+		requests = append(requests, r.URL.RawQuery)
+		page := len(requests)
+		w.Header().Set("Link", fmt.Sprintf(`<https://mock.myshopify.com/admin/orders.json?page_info=cGFnZS%d&limit=3>; rel="next"`, page))
+		fmt.Fprintf(w, `{"orders": [{"id": %d}, {"id": %d}]}`, page*10+1, page*10+2)
+	})
+	defer server.Close()
+
+	body, errs := mock.GetFirstN("orders", 3, map[string]string{"status": "any"})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"limit=3&status=any", "limit=3&page_info=cGFnZS1"}, requests)
+	var orders OrdersResponse
+	json.Unmarshal(body, &orders)
+	assert.Equal(t, 3, len(orders.Orders))
+	assert.Equal(t, int64(21), orders.Orders[2].ID)
+}
+
+// Should cap the page size to the endpoint's limit and stop at the last page
+func TestGetFirstNLastPage(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "250", r.URL.Query().Get("limit"))
+		w.Write([]byte(`{"products": [{"id": 1}, {"id": 2}]}`))
+	})
+	defer server.Close()
+
+	body, errs := mock.GetFirstN("products", 1000, nil)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, `{"products":[{"id":1},{"id":2}]}`, string(body))
+
+	_, errs = mock.GetFirstN("products", 0, nil)
+	assert.Equal(t, "invalid count 0, it must be positive", errs[0].Error())
+}
@@ -0,0 +1,71 @@
+package shopify
+
+import "fmt"
+
+const orderPaymentTermsQuery = `query($id: ID!) {
+  order(id: $id) {
+    paymentTerms {
+      paymentTermsName
+      paymentTermsType
+      dueInDays
+      overdue
+      paymentSchedules(first: 1) { edges { node { dueAt completedAt } } }
+    }
+  }
+}`
+
+//PaymentTerms are the terms a B2B customer has to pay an order by, e.g. "Net 30"
+type PaymentTerms struct {
+	Name string
+	// Type is one of RECEIPT, NET, FIXED or FULFILLMENT
+	Type string
+	// DueInDays is the number of days granted by net terms, 0 for the other types
+	DueInDays int
+	// DueAt is when the order is due, zero when it isn't scheduled yet, e.g. until fulfillment
+	DueAt ShopTime
+	// Paid tells whether the scheduled payment was completed
+	Paid    bool
+	Overdue bool
+}
+
+//GetOrderPaymentTerms returns the payment terms of an order, the zero PaymentTerms when the order has none
+func (shop *Shopify) GetOrderPaymentTerms(orderID int64) (PaymentTerms, []error) {
+	var data struct {
+		Order *struct {
+			PaymentTerms *struct {
+				PaymentTermsName string `json:"paymentTermsName"`
+				PaymentTermsType string `json:"paymentTermsType"`
+				DueInDays        *int   `json:"dueInDays"`
+				Overdue          bool   `json:"overdue"`
+				PaymentSchedules struct {
+					Edges []struct {
+						Node struct {
+							DueAt       ShopTime `json:"dueAt"`
+							CompletedAt ShopTime `json:"completedAt"`
+						} `json:"node"`
+					} `json:"edges"`
+				} `json:"paymentSchedules"`
+			} `json:"paymentTerms"`
+		} `json:"order"`
+	}
+	variables := map[string]interface{}{"id": GlobalID("Order", orderID)}
+	if errs := shop.graphQL(orderPaymentTermsQuery, variables, &data); len(errs) > 0 {
+		return PaymentTerms{}, errs
+	}
+	if data.Order == nil {
+		return PaymentTerms{}, []error{fmt.Errorf("order %v not found", orderID)}
+	}
+	terms := data.Order.PaymentTerms
+	if terms == nil {
+		return PaymentTerms{}, nil
+	}
+	paymentTerms := PaymentTerms{Name: terms.PaymentTermsName, Type: terms.PaymentTermsType, Overdue: terms.Overdue}
+	if terms.DueInDays != nil {
+		paymentTerms.DueInDays = *terms.DueInDays
+	}
+	if schedules := terms.PaymentSchedules.Edges; len(schedules) > 0 {
+		paymentTerms.DueAt = schedules[0].Node.DueAt
+		paymentTerms.Paid = !schedules[0].Node.CompletedAt.IsZero()
+	}
+	return paymentTerms, nil
+}
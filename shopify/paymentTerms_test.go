@@ -0,0 +1,45 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should decode the name, due date and overdue status of the order's payment terms
+func TestGetOrderPaymentTerms(t *testing.T) {
+	fixture := fixtureHandler(t, "/admin/api/graphql.json", "graphql_order_payment_terms.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "gid://shopify/Order/450789469", body["variables"]["id"])
+		fixture(w, r)
+	})
+	defer server.Close()
+
+	terms, errs := mock.GetOrderPaymentTerms(450789469)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "Net 30", terms.Name)
+	assert.Equal(t, "NET", terms.Type)
+	assert.Equal(t, 30, terms.DueInDays)
+	assert.Equal(t, time.Date(2021, 7, 9, 19, 20, 44, 0, time.UTC), terms.DueAt.UTC())
+	assert.T(t, !terms.Paid)
+	assert.T(t, terms.Overdue)
+}
+
+// Should return no terms for an order paid at checkout
+func TestGetOrderPaymentTermsNone(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"order": {"paymentTerms": null}}}`))
+	})
+	defer server.Close()
+
+	terms, errs := mock.GetOrderPaymentTerms(450789469)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, PaymentTerms{}, terms)
+}
@@ -0,0 +1,11 @@
+package shopify
+
+//GetPolicies returns the shop's legal policies (refund, privacy, terms of service...), they are read only
+func (shop *Shopify) GetPolicies() ([]Policy, []error) {
+	var policies PoliciesResponse
+	response, errors := shop.Get("policies")
+	if err := unmarshal(response, errors, &policies); len(err) > 0 {
+		return nil, err
+	}
+	return policies.Policies, nil
+}
@@ -0,0 +1,23 @@
+package shopify
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should decode the shop policies
+func TestGetPolicies(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/policies.json", "policies.json"))
+	defer server.Close()
+
+	policies, errs := mock.GetPolicies()
+
+	assert.T(t, errs == nil, errs)
+	var handles []string
+	for _, policy := range policies {
+		handles = append(handles, policy.Handle)
+	}
+	assert.Equal(t, []string{"refund-policy", "privacy-policy", "terms-of-service"}, handles)
+	assert.Equal(t, "Refund Policy", policies[0].Title)
+}
@@ -1,11 +1,32 @@
 package shopify
 
-import "fmt"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
 
 //GetProducts returns all the orders
 func (shopify *Shopify) GetProducts() ([]Product, []error) {
+	return shopify.GetProductsWithParameters(nil)
+}
+
+//GetProductsWithParameters returns the products matching the given parameters
+func (shopify *Shopify) GetProductsWithParameters(parameters map[string]string) ([]Product, []error) {
+	if err := shopify.checkLimit("products", parameters); err != nil {
+		return nil, []error{err}
+	}
 	var products ProductsResponse
-	response, errors := shopify.Get("products")
+	response, errors := shopify.GetWithParameters("products", parameters)
 	if err := unmarshal(response, errors, &products); len(err) > 0 {
 		return nil, err
 	}
@@ -22,6 +43,171 @@ func (shopify *Shopify) GetProduct(productID int64) (*Product, []error) {
 	return &product.Product, nil
 }
 
+//GetProductByHandle returns the product with the given handle, failing when none or several match
+func (shopify *Shopify) GetProductByHandle(handle string) (*Product, []error) {
+	products, errs := shopify.GetProductsWithParameters(map[string]string{"handle": handle})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	switch len(products) {
+	case 0:
+		return nil, []error{fmt.Errorf("no product found with handle %q", handle)}
+	case 1:
+		return &products[0], nil
+	default:
+		return nil, []error{fmt.Errorf("%d products found with handle %q", len(products), handle)}
+	}
+}
+
+//GetProductSummary returns the title, the number of variants and the inventory quantity of all variants
+//of a product, fetching only the fields needed to compute them
+func (shopify *Shopify) GetProductSummary(productID int64) (title string, variantCount int, totalInventory int, errs []error) {
+	var product ProductResponse
+	response, errors := shopify.GetWithParameters(fmt.Sprintf("products/%v", productID), map[string]string{"fields": "id,title,variants"})
+	if err := unmarshal(response, errors, &product); len(err) > 0 {
+		return "", 0, 0, err
+	}
+	for _, variant := range product.Product.Variants {
+		totalInventory += variant.InventoryQuantity
+	}
+	return product.Product.Title, len(product.Product.Variants), totalInventory, nil
+}
+
+//ProductSEO is what search engines show of a product, the title and description default to the
+//product's own ones when empty
+type ProductSEO struct {
+	Title       string
+	Description string
+	Handle      string
+}
+
+// Namespace and keys of the metafields holding a product's SEO title and description
+const (
+	seoMetafieldNamespace      = "global"
+	seoTitleMetafieldKey       = "title_tag"
+	seoDescriptionMetafieldKey = "description_tag"
+)
+
+//GetProductSEO returns the SEO title and description of a product along with its handle
+func (shopify *Shopify) GetProductSEO(productID int64) (ProductSEO, []error) {
+	var product ProductResponse
+	response, errors := shopify.GetWithParameters(fmt.Sprintf("products/%v", productID), map[string]string{"fields": "id,handle"})
+	if err := unmarshal(response, errors, &product); len(err) > 0 {
+		return ProductSEO{}, err
+	}
+	metafields, errs := shopify.GetMetafields("products", productID, map[string]string{"namespace": seoMetafieldNamespace})
+	if len(errs) > 0 {
+		return ProductSEO{}, errs
+	}
+	seo := ProductSEO{Handle: product.Product.Handle}
+	for _, metafield := range metafields {
+		switch metafield.Key {
+		case seoTitleMetafieldKey:
+			seo.Title = metafield.Value
+		case seoDescriptionMetafieldKey:
+			seo.Description = metafield.Value
+		}
+	}
+	return seo, nil
+}
+
+//SetProductSEO sets the SEO title and description of a product, and its handle unless empty
+func (shopify *Shopify) SetProductSEO(productID int64, seo ProductSEO) []error {
+	product := map[string]interface{}{
+		"metafields_global_title_tag":       seo.Title,
+		"metafields_global_description_tag": seo.Description,
+	}
+	if seo.Handle != "" {
+		product["handle"] = seo.Handle
+	}
+	_, errs := shopify.UpdateProduct(productID, product)
+	return errs
+}
+
+const productCategoryQuery = `query($id: ID!) {
+  product(id: $id) {
+    productCategory { productTaxonomyNode { id name fullName } }
+  }
+}`
+
+//ProductCategory is the node of shopify's standard product taxonomy a product is classified under
+type ProductCategory struct {
+	// ID is the global id of the taxonomy node, e.g. gid://shopify/ProductTaxonomyNode/352
+	ID   string
+	Name string
+	// FullName is the path of the node in the taxonomy, e.g. "Electronics > Audio > Headphones"
+	FullName string
+}
+
+//GetProductCategory returns the standard category of a product, the zero ProductCategory when it has none
+func (shopify *Shopify) GetProductCategory(productID int64) (ProductCategory, []error) {
+	var data struct {
+		Product *struct {
+			ProductCategory *struct {
+				ProductTaxonomyNode *struct {
+					ID       string `json:"id"`
+					Name     string `json:"name"`
+					FullName string `json:"fullName"`
+				} `json:"productTaxonomyNode"`
+			} `json:"productCategory"`
+		} `json:"product"`
+	}
+	if errs := shopify.graphQL(productCategoryQuery, map[string]interface{}{"id": GlobalID("Product", productID)}, &data); len(errs) > 0 {
+		return ProductCategory{}, errs
+	}
+	if data.Product == nil {
+		return ProductCategory{}, []error{fmt.Errorf("product %v not found", productID)}
+	}
+	if data.Product.ProductCategory == nil || data.Product.ProductCategory.ProductTaxonomyNode == nil {
+		return ProductCategory{}, nil
+	}
+	node := data.Product.ProductCategory.ProductTaxonomyNode
+	return ProductCategory{ID: node.ID, Name: node.Name, FullName: node.FullName}, nil
+}
+
+// errVariantFound stops the scan of findVariant's pages once the variant is found
+var errVariantFound = errors.New("variant found")
+
+//GetVariantBySKU returns the first variant with the given SKU along with its product id. Shopify can't filter
+//products by SKU, so this is a client side scan of every product's variants, fetching only their ids and variants.
+func (shopify *Shopify) GetVariantBySKU(sku string) (*Variant, int64, []error) {
+	return shopify.findVariant(fmt.Sprintf("SKU %q", sku), func(variant Variant) bool { return variant.SKU == sku })
+}
+
+//GetVariantByBarcode returns the first variant with the given barcode along with its product id.
+//Like GetVariantBySKU, it scans every product's variants client side.
+func (shopify *Shopify) GetVariantByBarcode(barcode string) (*Variant, int64, []error) {
+	return shopify.findVariant(fmt.Sprintf("barcode %q", barcode), func(variant Variant) bool { return variant.BarCode == barcode })
+}
+
+// findVariant pages the products until a variant matches, described tells what was looked for in the error
+func (shopify *Shopify) findVariant(described string, match func(Variant) bool) (*Variant, int64, []error) {
+	var found *Variant
+	var productID int64
+	errs := shopify.paginate("products", map[string]string{"fields": "id,variants", "limit": "250"}, func(page []byte) []error {
+		var products ProductsResponse
+		if err := unmarshal(page, nil, &products); len(err) > 0 {
+			return err
+		}
+		for _, product := range products.Products {
+			for i := range product.Variants {
+				if match(product.Variants[i]) {
+					found, productID = &product.Variants[i], product.ID
+					return []error{errVariantFound}
+				}
+			}
+		}
+		return nil
+	})
+	if found != nil {
+		return found, productID, nil
+	}
+	if len(errs) > 0 {
+		return nil, 0, errs
+	}
+	return nil, 0, []error{fmt.Errorf("no variant found with %v", described)}
+}
+
 //GetProductImages returns all the orders
 func (shopify *Shopify) GetProductImages(productID int64) ([]ProductImage, []error) {
 	var images ImagesResponse
@@ -41,3 +227,506 @@ func (shopify *Shopify) GetProductVariants(productID int64) ([]Variant, []error)
 	}
 	return variants.Variants, nil
 }
+
+//GetVariant returns a variant given its id
+func (shopify *Shopify) GetVariant(variantID int64) (*Variant, []error) {
+	var variant VariantResponse
+	response, errors := shopify.Get(fmt.Sprintf("variants/%v", variantID))
+	if err := unmarshal(response, errors, &variant); len(err) > 0 {
+		return nil, err
+	}
+	return &variant.Variant, nil
+}
+
+//SetVariantInventoryPolicy sets whether customers can buy the variant when it is out of stock, policy being
+//"deny" or "continue", and whether shopify tracks its inventory
+func (shopify *Shopify) SetVariantInventoryPolicy(variantID int64, policy string, tracked bool) (*Variant, []error) {
+	if policy != "deny" && policy != "continue" {
+		return nil, []error{fmt.Errorf("invalid inventory policy %q, it must be deny or continue", policy)}
+	}
+	var management interface{}
+	if tracked {
+		management = "shopify"
+	}
+	var variant VariantResponse
+	response, errors := shopify.Put(fmt.Sprintf("variants/%v", variantID), map[string]interface{}{"variant": map[string]interface{}{
+		"id":                   variantID,
+		"inventory_policy":     policy,
+		"inventory_management": management,
+	}})
+	if err := unmarshal(response, errors, &variant); len(err) > 0 {
+		return nil, err
+	}
+	return &variant.Variant, nil
+}
+
+//PriceResult is the outcome of adjusting the price of one of the variants given to AdjustVariantPrices
+type PriceResult struct {
+	// Index of the variant id in the input slice
+	Index     int
+	VariantID int64
+	// OldPrice and NewPrice are the price before and after the change, NewPrice is only set on success
+	OldPrice Money
+	NewPrice Money
+	// Error returned by shopify, nil on success
+	Error *ShopifyError
+}
+
+//AdjustVariantPrices changes the price of each variant either by a percentage, e.g. 10 raises the
+//prices by 10% and -25 lowers them by a quarter, or by a fixed amount such as "-5.00". Exactly one of pct
+//and fixedDelta must be given. The new prices are computed on decimals and rounded to the cent, halves
+//away from zero, and the requests are paced by the rate limiter. A variant rejected by shopify does not
+//stop the batch and is reported in its PriceResult instead.
+func (shopify *Shopify) AdjustVariantPrices(variantIDs []int64, pct float64, fixedDelta string) ([]PriceResult, []error) {
+	if (pct != 0) == (fixedDelta != "") {
+		return nil, []error{fmt.Errorf("exactly one of a percentage and a fixed delta must be given")}
+	}
+	adjust, err := priceAdjustment(pct, fixedDelta)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var errs []error
+	results := make([]PriceResult, len(variantIDs))
+	for i, variantID := range variantIDs {
+		results[i] = PriceResult{Index: i, VariantID: variantID}
+		variant, errors := shopify.GetVariant(variantID)
+		if shopifyError := findShopifyError(errors); shopifyError != nil {
+			results[i].Error = shopifyError
+			continue
+		}
+		if len(errors) > 0 {
+			errs = append(errs, errors...)
+			continue
+		}
+		results[i].OldPrice = variant.Price
+		price, ok := new(big.Rat).SetString(variant.Price.Amount)
+		if !ok {
+			errs = append(errs, fmt.Errorf("variant %v: invalid price %q", variantID, variant.Price.Amount))
+			continue
+		}
+		newPrice := adjust(price)
+		if newPrice.Sign() < 0 {
+			errs = append(errs, fmt.Errorf("variant %v: the price %v would become negative", variantID, variant.Price.Amount))
+			continue
+		}
+
+		var variantResponse VariantResponse
+		response, errors := shopify.Put(fmt.Sprintf("variants/%v", variantID), map[string]interface{}{"variant": map[string]interface{}{
+			"id":    variantID,
+			"price": newPrice.FloatString(2),
+		}})
+		if shopifyError := findShopifyError(errors); shopifyError != nil {
+			results[i].Error = shopifyError
+			continue
+		}
+		if err := unmarshal(response, errors, &variantResponse); len(err) > 0 {
+			errs = append(errs, err...)
+			continue
+		}
+		results[i].NewPrice = variantResponse.Variant.Price
+	}
+	return results, errs
+}
+
+// priceAdjustment returns the function computing a new price from the current one, by a percentage or a fixed delta
+func priceAdjustment(pct float64, fixedDelta string) (func(price *big.Rat) *big.Rat, error) {
+	if fixedDelta != "" {
+		delta, ok := new(big.Rat).SetString(fixedDelta)
+		if !ok {
+			return nil, fmt.Errorf("invalid fixed delta %q", fixedDelta)
+		}
+		return func(price *big.Rat) *big.Rat {
+			return new(big.Rat).Add(price, delta)
+		}, nil
+	}
+	// going through the shortest decimal representation keeps e.g. 10.1 from becoming 10.0999...
+	percentage, ok := new(big.Rat).SetString(strconv.FormatFloat(pct, 'f', -1, 64))
+	if !ok {
+		return nil, fmt.Errorf("invalid percentage %v", pct)
+	}
+	factor := new(big.Rat).Add(big.NewRat(1, 1), new(big.Rat).Quo(percentage, big.NewRat(100, 1)))
+	return func(price *big.Rat) *big.Rat {
+		return new(big.Rat).Mul(price, factor)
+	}, nil
+}
+
+//GetProductsByIDs returns the products with the given ids keyed by id, ids that don't resolve to a
+//product are left out of the map. The ids are fetched concurrently through the rate limiter in
+//chunks of the largest page size, a chunk that fails doesn't affect the others.
+func (shopify *Shopify) GetProductsByIDs(ids []int64) (map[int64]Product, []error) {
+	seen := make(map[int64]bool)
+	var unique []string
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, strconv.FormatInt(id, 10))
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		errs     []error
+		products = make(map[int64]Product)
+		size     = shopify.maxLimit("products")
+	)
+	for start := 0; start < len(unique); start += size {
+		end := start + size
+		if end > len(unique) {
+			end = len(unique)
+		}
+		wg.Add(1)
+		go func(chunk []string) {
+			defer wg.Done()
+			found, err := shopify.GetProductsWithParameters(map[string]string{
+				"ids":   strings.Join(chunk, ","),
+				"limit": strconv.Itoa(len(chunk)),
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err...)
+			for _, product := range found {
+				products[product.ID] = product
+			}
+		}(unique[start:end])
+	}
+	wg.Wait()
+	return products, errs
+}
+
+//GetProductRecommendations returns the products the storefront recommends alongside the given one,
+//an empty slice when there are none
+func (shopify *Shopify) GetProductRecommendations(productID int64) ([]Product, []error) {
+	var products ProductsResponse
+	parameters := map[string]string{"product_id": fmt.Sprintf("%v", productID)}
+	_, response, errors := shopify.send("GET", shopify.createPrefixedURL(recommendationsPrefix, "products", parameters), nil)
+	if err := unmarshal(response, errors, &products); len(err) > 0 {
+		return nil, err
+	}
+	if products.Products == nil {
+		return []Product{}, nil
+	}
+	return products.Products, nil
+}
+
+// maxProductOptions is the number of options a product can have, matching a variant's option1 to option3
+const maxProductOptions = 3
+
+//CreateProduct creates a product along with its options and variants, e.g. built by BuildProductOptions
+//and BuildVariantMatrix. Each variant must set one value per option, and no two variants the same values.
+func (shopify *Shopify) CreateProduct(product Product) (*Product, []error) {
+	if err := validateProductVariants(product); err != nil {
+		return nil, []error{err}
+	}
+	var productResponse ProductResponse
+	response, errors := shopify.Post("products", map[string]interface{}{"product": newProductBody(product)})
+	if err := unmarshal(response, errors, &productResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &productResponse.Product, nil
+}
+
+// newProductBody keeps the fields of a new product and of its variants and images that are set, leaving
+// the read only and the zero ones to shopify's defaults, e.g. a variant is taxable unless told otherwise
+func newProductBody(product Product) map[string]interface{} {
+	body := diffFields(toJSONMap(Product{}), toJSONMap(product))
+	delete(body, "variants")
+	delete(body, "images")
+	if len(product.Variants) > 0 {
+		variants := make([]map[string]interface{}, len(product.Variants))
+		for i, variant := range product.Variants {
+			variants[i] = withoutReadOnlyFields(diffFields(toJSONMap(Variant{}), toJSONMap(variant)),
+				"inventory_item_id", "inventory_quantity")
+		}
+		body["variants"] = variants
+	}
+	if len(product.Images) > 0 {
+		images := make([]map[string]interface{}, len(product.Images))
+		for i, image := range product.Images {
+			images[i] = diffFields(toJSONMap(ProductImage{}), toJSONMap(image))
+		}
+		body["images"] = images
+	}
+	return body
+}
+
+//BuildProductOptions returns the options of a product with the given option names and values,
+//ordered by name like the option fields of the variants of BuildVariantMatrix
+func BuildProductOptions(options map[string][]string) []map[string]interface{} {
+	var productOptions []map[string]interface{}
+	for _, name := range sortedOptionNames(options) {
+		productOptions = append(productOptions, map[string]interface{}{"name": name, "values": options[name]})
+	}
+	return productOptions
+}
+
+//BuildVariantMatrix returns one variant per combination of the option values, e.g. 6 variants for
+//2 sizes and 3 colors. The options are ordered by name, the alphabetically first one filling option1,
+//and the values keep their order. Prices, SKUs and the other fields are left to the caller.
+func BuildVariantMatrix(options map[string][]string) []Variant {
+	names := sortedOptionNames(options)
+	if len(names) == 0 {
+		return nil
+	}
+	combinations := [][]string{{}}
+	for _, name := range names {
+		var next [][]string
+		for _, combination := range combinations {
+			for _, value := range options[name] {
+				next = append(next, append(append([]string{}, combination...), value))
+			}
+		}
+		combinations = next
+	}
+	variants := make([]Variant, len(combinations))
+	for i, combination := range combinations {
+		fields := []*string{&variants[i].Option1, &variants[i].Option2, &variants[i].Option3}
+		for j, value := range combination {
+			if j < len(fields) {
+				*fields[j] = value
+			}
+		}
+	}
+	return variants
+}
+
+// sortedOptionNames returns the option names in the order they are given to the variants
+func sortedOptionNames(options map[string][]string) []string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateProductVariants checks the variants set one value per option of the product and differ from each other
+func validateProductVariants(product Product) error {
+	if len(product.Options) > maxProductOptions {
+		return fmt.Errorf("a product has at most %d options, got %d", maxProductOptions, len(product.Options))
+	}
+	seen := make(map[[maxProductOptions]string]bool)
+	for i, variant := range product.Variants {
+		values := [maxProductOptions]string{variant.Option1, variant.Option2, variant.Option3}
+		count := 0
+		for _, value := range values {
+			if value != "" {
+				count++
+			}
+		}
+		if len(product.Options) > 0 && count != len(product.Options) {
+			return fmt.Errorf("variant %d has %d option values, the product has %d options", i, count, len(product.Options))
+		}
+		if count > 0 && seen[values] {
+			return fmt.Errorf("variant %d repeats the option values %q", i, values[:count])
+		}
+		seen[values] = true
+	}
+	return nil
+}
+
+//ProductResult is the outcome of creating one of the products given to CreateProducts
+type ProductResult struct {
+	// Index of the product in the input slice
+	Index int
+	// Product as created by shopify, nil on failure
+	Product *Product
+	// Error returned by shopify, nil on success
+	Error *ShopifyError
+}
+
+//CreateProducts creates the given products one by one, a product rejected by shopify
+//does not stop the batch and is reported in its ProductResult instead
+func (shopify *Shopify) CreateProducts(products []Product) ([]ProductResult, []error) {
+	var errs []error
+	results := make([]ProductResult, len(products))
+	for i, product := range products {
+		results[i].Index = i
+		var productResponse ProductResponse
+		response, errors := shopify.Post("products", map[string]interface{}{"product": newProductBody(product)})
+		if shopifyError := findShopifyError(errors); shopifyError != nil {
+			results[i].Error = shopifyError
+			continue
+		}
+		if err := unmarshal(response, errors, &productResponse); len(err) > 0 {
+			errs = append(errs, err...)
+			continue
+		}
+		results[i].Product = &productResponse.Product
+	}
+	return results, errs
+}
+
+//DeleteResult is the outcome of deleting one of the products given to DeleteProducts
+type DeleteResult struct {
+	// Index of the product id in the input slice
+	Index int
+	// ID of the product
+	ID int64
+	// Error returned by shopify, nil on success or when the product was already gone
+	Error *ShopifyError
+}
+
+//DeleteProducts deletes the given products concurrently, the rate limiter pacing the requests.
+//A 404 counts as a success since the product is already gone, any other answer of shopify
+//is reported in the DeleteResult of the product.
+func (shopify *Shopify) DeleteProducts(ids []int64) ([]DeleteResult, []error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		errs    []error
+		results = make([]DeleteResult, len(ids))
+	)
+	for i, id := range ids {
+		results[i] = DeleteResult{Index: i, ID: id}
+		wg.Add(1)
+		go func(i int, id int64) {
+			defer wg.Done()
+			_, errors := shopify.Delete(fmt.Sprintf("products/%v", id))
+			if shopifyError := findShopifyError(errors); shopifyError != nil {
+				if shopifyError.StatusCode != http.StatusNotFound {
+					results[i].Error = shopifyError
+				}
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, e := range errors {
+				errs = append(errs, fmt.Errorf("product %v: %w", id, e))
+			}
+		}(i, id)
+	}
+	wg.Wait()
+	return results, errs
+}
+
+//DuplicateProduct creates an unpublished draft copy of a product under a new title. The copy gets
+//the options, variants and images of the source without any of their ids, so shopify creates new ones.
+func (shopify *Shopify) DuplicateProduct(productID int64, newTitle string) (*Product, []error) {
+	source, errs := shopify.GetProduct(productID)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	product := withoutReadOnlyFields(toJSONMap(source), "handle", "published_at")
+	product["title"] = newTitle
+	product["status"] = "draft"
+	product["published"] = false
+	for key, extra := range map[string][]string{
+		"options":  nil,
+		"variants": {"inventory_item_id", "inventory_quantity", "image_id"},
+		"images":   {"variant_ids"},
+	} {
+		items, _ := product[key].([]interface{})
+		for _, item := range items {
+			if fields, ok := item.(map[string]interface{}); ok {
+				withoutReadOnlyFields(fields, extra...)
+			}
+		}
+	}
+
+	var productResponse ProductResponse
+	response, errors := shopify.Post("products", map[string]interface{}{"product": product})
+	if err := unmarshal(response, errors, &productResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &productResponse.Product, nil
+}
+
+//UpdateProduct updates an existing product with the given fields
+func (shopify *Shopify) UpdateProduct(productID int64, product map[string]interface{}) (*Product, []error) {
+	var productResponse ProductResponse
+	product["id"] = productID
+	response, errors := shopify.Put(fmt.Sprintf("products/%v", productID), map[string]interface{}{"product": product})
+	if err := unmarshal(response, errors, &productResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &productResponse.Product, nil
+}
+
+// Fields shopify manages on its own and are never part of an update
+var readOnlyProductFields = map[string]bool{"id": true, "created_at": true, "updated_at": true, "updatedAt": true, "product_id": true}
+
+//PublishProduct publishes a product on the online store at the given time, right away when it's zero
+func (shopify *Shopify) PublishProduct(productID int64, publishAt time.Time) (*Product, []error) {
+	fields := map[string]interface{}{"published": true}
+	if !publishAt.IsZero() {
+		fields["published_at"] = publishAt.Format(time.RFC3339)
+	}
+	return shopify.UpdateProduct(productID, fields)
+}
+
+//UnpublishProduct hides a product from the online store
+func (shopify *Shopify) UnpublishProduct(productID int64) (*Product, []error) {
+	return shopify.UpdateProduct(productID, map[string]interface{}{"published": false, "published_at": nil})
+}
+
+//ProductUpdateDiff returns the minimal body for UpdateProduct that turns current into desired.
+//When the variants differ, every desired variant is listed since shopify deletes the omitted ones:
+//unchanged variants only carry their id, changed ones their id and changed fields, and new ones
+//(without id) all their non zero fields.
+func ProductUpdateDiff(current, desired Product) map[string]interface{} {
+	diff := diffFields(toJSONMap(current), toJSONMap(desired))
+	delete(diff, "variants")
+
+	currentVariants := make(map[int64]Variant)
+	for _, variant := range current.Variants {
+		currentVariants[variant.ID] = variant
+	}
+
+	variantsChanged := len(current.Variants) != len(desired.Variants)
+	variants := make([]map[string]interface{}, 0, len(desired.Variants))
+	for _, variant := range desired.Variants {
+		existing, found := currentVariants[variant.ID]
+		if variant.ID == 0 || !found {
+			existing = Variant{}
+		}
+		variantDiff := diffFields(toJSONMap(existing), toJSONMap(variant))
+		if len(variantDiff) > 0 || !found {
+			variantsChanged = true
+		}
+		if variant.ID != 0 {
+			variantDiff["id"] = variant.ID
+		}
+		variants = append(variants, variantDiff)
+	}
+	if variantsChanged {
+		diff["variants"] = variants
+	}
+	return diff
+}
+
+// diffFields returns the desired values of the fields that differ from current, skipping read only ones
+func diffFields(current, desired map[string]interface{}) map[string]interface{} {
+	diff := make(map[string]interface{})
+	for key, value := range desired {
+		if readOnlyProductFields[key] {
+			continue
+		}
+		if !reflect.DeepEqual(current[key], value) {
+			diff[key] = value
+		}
+	}
+	return diff
+}
+
+// withoutReadOnlyFields deletes the read only fields and the extra ones from fields and returns it
+func withoutReadOnlyFields(fields map[string]interface{}, extra ...string) map[string]interface{} {
+	for key := range readOnlyProductFields {
+		delete(fields, key)
+	}
+	for _, key := range extra {
+		delete(fields, key)
+	}
+	return fields
+}
+
+// toJSONMap converts a struct into the map of its json fields, keeping numbers as json.Number
+func toJSONMap(value interface{}) map[string]interface{} {
+	data, _ := json.Marshal(value)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	fields := make(map[string]interface{})
+	decoder.Decode(&fields)
+	return fields
+}
@@ -0,0 +1,729 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should decode a single product with its variants and images
+func TestGetProductMock(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/products/632910392.json", "product.json"))
+	defer server.Close()
+
+	product, errs := mock.GetProduct(632910392)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "ipod-nano", product.Handle)
+	assert.Equal(t, 2, len(product.Variants))
+	assert.Equal(t, "IPOD2008RED", product.Variants[1].SKU)
+	assert.Equal(t, 1, len(product.Images))
+}
+
+// Should only ask for the summary fields and add up the variants inventory
+func TestGetProductSummary(t *testing.T) {
+	fixture := fixtureHandler(t, "/admin/products/632910392.json", "product.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "id,title,variants", r.URL.Query().Get("fields"))
+		fixture(w, r)
+	})
+	defer server.Close()
+
+	title, variantCount, totalInventory, errs := mock.GetProductSummary(632910392)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "IPod Nano - 8GB", title)
+	assert.Equal(t, 2, variantCount)
+	assert.Equal(t, 30, totalInventory)
+}
+
+// Should create a draft copy under the new title without any of the source ids
+func TestDuplicateProduct(t *testing.T) {
+	fixture := loadFixture(t, "product.json")
+	var created map[string]interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			assert.Equal(t, "/admin/products/632910392.json", r.URL.Path)
+			w.Write(fixture)
+			return
+		}
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/admin/products.json", r.URL.Path)
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		created = body["product"]
+		w.WriteHeader(201)
+		w.Write([]byte(`{"product": {"id": 1071559575, "title": "IPod Nano - 8GB copy", "status": "draft"}}`))
+	})
+	defer server.Close()
+
+	product, errs := mock.DuplicateProduct(632910392, "IPod Nano - 8GB copy")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, int64(1071559575), product.ID)
+	assert.Equal(t, "IPod Nano - 8GB copy", created["title"])
+	assert.Equal(t, "draft", created["status"])
+	assert.Equal(t, false, created["published"])
+	assert.Equal(t, "Apple", created["vendor"])
+	for _, key := range []string{"id", "handle", "created_at"} {
+		_, found := created[key]
+		assert.T(t, !found, key)
+	}
+	variants := created["variants"].([]interface{})
+	assert.Equal(t, 2, len(variants))
+	for _, item := range append(variants, created["images"].([]interface{})...) {
+		fields := item.(map[string]interface{})
+		for _, key := range []string{"id", "product_id", "inventory_item_id", "variant_ids"} {
+			_, found := fields[key]
+			assert.T(t, !found, key)
+		}
+	}
+	assert.Equal(t, "IPOD2008RED", variants[1].(map[string]interface{})["sku"])
+	assert.Equal(t, "Red", variants[1].(map[string]interface{})["option1"])
+}
+
+// Should fetch the ids in chunks of 250 and map the products that exist
+func TestGetProductsByIDs(t *testing.T) {
+	var mu sync.Mutex
+	var chunks []int
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/products.json", r.URL.Path)
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		assert.Equal(t, strconv.Itoa(len(ids)), r.URL.Query().Get("limit"))
+		mu.Lock()
+		chunks = append(chunks, len(ids))
+		mu.Unlock()
+		var products []string
+		for _, id := range ids {
+			// the ids above 290 don't exist
+			if n, _ := strconv.Atoi(id); n <= 290 {
+				products = append(products, fmt.Sprintf(`{"id": %v, "title": "Product %v"}`, id, id))
+			}
+		}
+		w.Write([]byte(`{"products": [` + strings.Join(products, ",") + `]}`))
+	})
+	defer server.Close()
+
+	var ids []int64
+	for id := int64(1); id <= 300; id++ {
+		ids = append(ids, id)
+	}
+	products, errs := mock.GetProductsByIDs(append(ids, 1, 2))
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(chunks))
+	assert.Equal(t, 300, chunks[0]+chunks[1])
+	assert.Equal(t, 290, len(products))
+	assert.Equal(t, "Product 250", products[250].Title)
+	_, found := products[291]
+	assert.T(t, !found)
+}
+
+// productUpdateHandler serves the product fixture and hands the decoded PUT body to check
+func productUpdateHandler(t *testing.T, check func(product map[string]interface{})) http.HandlerFunc {
+	fixture := loadFixture(t, "product.json")
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/admin/products/632910392.json", r.URL.Path)
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		check(body["product"])
+		w.Write(fixture)
+	}
+}
+
+// Should PUT the publication date in RFC3339
+func TestPublishProduct(t *testing.T) {
+	mock, server := newMockShopify(t, productUpdateHandler(t, func(product map[string]interface{}) {
+		assert.Equal(t, true, product["published"])
+		assert.Equal(t, "2019-03-01T09:00:00-05:00", product["published_at"])
+	}))
+	defer server.Close()
+
+	publishAt := time.Date(2019, 3, 1, 9, 0, 0, 0, time.FixedZone("EST", -5*3600))
+	_, errs := mock.PublishProduct(632910392, publishAt)
+
+	assert.T(t, errs == nil, errs)
+}
+
+// Should PUT a null publication date
+func TestUnpublishProduct(t *testing.T) {
+	mock, server := newMockShopify(t, productUpdateHandler(t, func(product map[string]interface{}) {
+		assert.Equal(t, false, product["published"])
+		publishedAt, found := product["published_at"]
+		assert.T(t, found)
+		assert.T(t, publishedAt == nil)
+	}))
+	defer server.Close()
+
+	_, errs := mock.UnpublishProduct(632910392)
+
+	assert.T(t, errs == nil, errs)
+}
+
+// variantPagesHandler serves two pages of products and the variants of a third one that must not be reached
+func variantPagesHandler(t *testing.T, pages *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/products.json", r.URL.Path)
+		assert.Equal(t, "id,variants", r.URL.Query().Get("fields"))
+		pageInfo := r.URL.Query().Get("page_info")
+		*pages = append(*pages, pageInfo)
+		switch pageInfo {
+		case "":
+			w.Header().Set("Link", `<https://mock.myshopify.com/admin/products.json?page_info=cGFnZTI&limit=250>; rel="next"`)
+			w.Write([]byte(`{"products": [{"id": 632910392, "variants": [
+				{"id": 808950810, "sku": "IPOD2008PINK", "barcode": "1234_pink"},
+				{"id": 49148385, "sku": "IPOD2008RED", "barcode": "1234_red"}]}]}`))
+		case "cGFnZTI":
+			w.Header().Set("Link", `<https://mock.myshopify.com/admin/products.json?page_info=cGFnZTM&limit=250>; rel="next"`)
+			w.Write([]byte(`{"products": [{"id": 921728736, "variants": [{"id": 447654529, "sku": "IPOD2009BLACK", "barcode": "1234_black"}]}]}`))
+		default:
+			w.Write([]byte(`{"products": [{"id": 1071559574, "variants": [{"id": 1, "sku": "SHUFFLE", "barcode": "1234_shuffle"}]}]}`))
+		}
+	}
+}
+
+// Should scan the pages until the SKU is found
+func TestGetVariantBySKU(t *testing.T) {
+	var pages []string
+	mock, server := newMockShopify(t, variantPagesHandler(t, &pages))
+	defer server.Close()
+
+	variant, productID, errs := mock.GetVariantBySKU("IPOD2009BLACK")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, int64(447654529), variant.ID)
+	assert.Equal(t, int64(921728736), productID)
+	assert.Equal(t, []string{"", "cGFnZTI"}, pages)
+}
+
+// Should match the barcode and fail when no variant has it
+func TestGetVariantByBarcode(t *testing.T) {
+	var pages []string
+	mock, server := newMockShopify(t, variantPagesHandler(t, &pages))
+	defer server.Close()
+
+	variant, productID, errs := mock.GetVariantByBarcode("1234_red")
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "IPOD2008RED", variant.SKU)
+	assert.Equal(t, int64(632910392), productID)
+
+	variant, _, errs = mock.GetVariantByBarcode("0000")
+	assert.T(t, variant == nil)
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, `no variant found with barcode "0000"`, errs[0].Error())
+}
+
+// Should decode the products recommended for a product
+func TestGetProductRecommendations(t *testing.T) {
+	fixture := loadFixture(t, "recommendations.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/recommendations/products.json", r.URL.Path)
+		assert.Equal(t, "632910392", r.URL.Query().Get("product_id"))
+		w.Write(fixture)
+	})
+	defer server.Close()
+
+	products, errs := mock.GetProductRecommendations(632910392)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(products))
+	assert.Equal(t, "ipod-touch", products[0].Handle)
+	assert.Equal(t, int64(921728736), products[0].ID)
+}
+
+// Should return an empty slice when nothing is recommended
+func TestGetProductRecommendationsEmpty(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"intent": "related", "products": []}`))
+	})
+	defer server.Close()
+
+	products, errs := mock.GetProductRecommendations(632910392)
+
+	assert.T(t, errs == nil, errs)
+	assert.T(t, products != nil)
+	assert.Equal(t, 0, len(products))
+}
+
+// Should put the inventory policy and management of the variant
+func TestSetVariantInventoryPolicy(t *testing.T) {
+	var bodies []map[string]map[string]interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/admin/variants/808950810.json", r.URL.Path)
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		json.NewEncoder(w).Encode(body)
+	})
+	defer server.Close()
+
+	variant, errs := mock.SetVariantInventoryPolicy(808950810, "continue", true)
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "continue", variant.InventoryPolicy)
+	assert.Equal(t, "shopify", variant.InventoryManagement)
+
+	_, errs = mock.SetVariantInventoryPolicy(808950810, "deny", false)
+	assert.T(t, errs == nil, errs)
+
+	_, errs = mock.SetVariantInventoryPolicy(808950810, "allow", true)
+	assert.Equal(t, "invalid inventory policy \"allow\", it must be deny or continue", errs[0].Error())
+
+	assert.Equal(t, 2, len(bodies))
+	assert.Equal(t, map[string]interface{}{
+		"id":                   float64(808950810),
+		"inventory_policy":     "continue",
+		"inventory_management": "shopify",
+	}, bodies[0]["variant"])
+	assert.Equal(t, map[string]interface{}{
+		"id":                   float64(808950810),
+		"inventory_policy":     "deny",
+		"inventory_management": nil,
+	}, bodies[1]["variant"])
+}
+
+// priceHandler serves variants with the given prices, records and echoes the PUT ones and rejects the rejected variant
+func priceHandler(prices map[string]string, rejected string, put *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/admin/variants/"), ".json")
+		if r.Method == "GET" {
+			fmt.Fprintf(w, `{"variant": {"id": %s, "price": %q}}`, id, prices[id])
+			return
+		}
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		*put = append(*put, id+"="+body["variant"]["price"].(string))
+		if id == rejected {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"errors": {"price": ["must be less than 1000000000000000000"]}}`))
+			return
+		}
+		fmt.Fprintf(w, `{"variant": {"id": %s, "price": %q}}`, id, body["variant"]["price"])
+	}
+}
+
+// Should raise the prices by the percentage rounding halves up to the cent, and report the rejected ones
+func TestAdjustVariantPricesPercentage(t *testing.T) {
+	var put []string
+	prices := map[string]string{"1": "19.99", "2": "0.05", "3": "10.00", "4": "2.00"}
+	mock, server := newMockShopify(t, priceHandler(prices, "3", &put))
+	defer server.Close()
+
+	results, errs := mock.AdjustVariantPrices([]int64{1, 2, 3, 4}, 10, "")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"1=21.99", "2=0.06", "3=11.00", "4=2.20"}, put)
+	assert.Equal(t, 4, len(results))
+	for i, id := range []int64{1, 2, 3, 4} {
+		assert.Equal(t, i, results[i].Index)
+		assert.Equal(t, id, results[i].VariantID)
+	}
+	assert.Equal(t, Money{Amount: "19.99"}, results[0].OldPrice)
+	assert.Equal(t, Money{Amount: "21.99"}, results[0].NewPrice)
+	assert.Equal(t, Money{Amount: "0.06"}, results[1].NewPrice)
+	assert.Equal(t, http.StatusUnprocessableEntity, results[2].Error.StatusCode)
+	assert.Equal(t, Money{}, results[2].NewPrice)
+	assert.Equal(t, Money{Amount: "2.20"}, results[3].NewPrice)
+}
+
+// Should add the fixed delta and refuse to make a price negative
+func TestAdjustVariantPricesFixed(t *testing.T) {
+	var put []string
+	mock, server := newMockShopify(t, priceHandler(map[string]string{"1": "19.99", "2": "3.50"}, "", &put))
+	defer server.Close()
+
+	results, errs := mock.AdjustVariantPrices([]int64{1, 2}, 0, "-5.00")
+
+	assert.Equal(t, []string{"1=14.99"}, put)
+	assert.Equal(t, Money{Amount: "14.99"}, results[0].NewPrice)
+	assert.Equal(t, Money{}, results[1].NewPrice)
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, "variant 2: the price 3.50 would become negative", errs[0].Error())
+}
+
+// Should require exactly one valid kind of change before calling shopify
+func TestAdjustVariantPricesInvalid(t *testing.T) {
+	_, errs := shop.AdjustVariantPrices([]int64{1}, 10, "1.00")
+	assert.Equal(t, "exactly one of a percentage and a fixed delta must be given", errs[0].Error())
+	_, errs = shop.AdjustVariantPrices([]int64{1}, 0, "")
+	assert.Equal(t, "exactly one of a percentage and a fixed delta must be given", errs[0].Error())
+	_, errs = shop.AdjustVariantPrices([]int64{1}, 0, "five")
+	assert.Equal(t, `invalid fixed delta "five"`, errs[0].Error())
+}
+
+// Should delete the products concurrently, count a 404 as deleted and report the other failures
+func TestDeleteProducts(t *testing.T) {
+	var mu sync.Mutex
+	var deleted []string
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		mu.Lock()
+		deleted = append(deleted, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/admin/products/632910392.json":
+			w.Write([]byte(`{}`))
+		case "/admin/products/921728736.json":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":"Not Found"}`))
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"errors":{"base":["Product is part of an active bundle"]}}`))
+		}
+	})
+	defer server.Close()
+
+	results, errs := mock.DeleteProducts([]int64{632910392, 921728736, 543210987})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 3, len(deleted))
+	assert.Equal(t, 3, len(results))
+	for i, id := range []int64{632910392, 921728736, 543210987} {
+		assert.Equal(t, i, results[i].Index)
+		assert.Equal(t, id, results[i].ID)
+	}
+	assert.T(t, results[0].Error == nil)
+	assert.T(t, results[1].Error == nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, results[2].Error.StatusCode)
+	assert.Equal(t, ErrorMessages{"base": {"Product is part of an active bundle"}}, results[2].Error.Errors)
+}
+
+// Should generate one variant per combination of a 2x3 option set, options ordered by name
+func TestBuildVariantMatrix(t *testing.T) {
+	variants := BuildVariantMatrix(map[string][]string{"Size": {"S", "M", "L"}, "Color": {"Black", "White"}})
+
+	assert.Equal(t, []Variant{
+		{Option1: "Black", Option2: "S"},
+		{Option1: "Black", Option2: "M"},
+		{Option1: "Black", Option2: "L"},
+		{Option1: "White", Option2: "S"},
+		{Option1: "White", Option2: "M"},
+		{Option1: "White", Option2: "L"},
+	}, variants)
+	assert.Equal(t, 0, len(BuildVariantMatrix(nil)))
+}
+
+// Should post the options and the variant matrix of the product
+func TestCreateProduct(t *testing.T) {
+	var posted map[string]interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/admin/products.json", r.URL.Path)
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		posted = body["product"]
+		w.WriteHeader(201)
+		w.Write([]byte(`{"product": {"id": 1071559748, "title": "Burton Custom Freestyle 151",
+			"options": [{"name": "Color", "position": 1, "values": ["Black", "White"]}, {"name": "Size", "position": 2, "values": ["S", "M", "L"]}],
+			"variants": [{"id": 1, "option1": "Black", "option2": "S"}, {"id": 2, "option1": "Black", "option2": "M"}]}}`))
+	})
+	defer server.Close()
+
+	options := map[string][]string{"Size": {"S", "M", "L"}, "Color": {"Black", "White"}}
+	matrix := BuildVariantMatrix(options)
+	matrix[0].Price = Money{Amount: "449.00"}
+	product, errs := mock.CreateProduct(Product{
+		Title:    "Burton Custom Freestyle 151",
+		Options:  BuildProductOptions(options),
+		Variants: matrix,
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, int64(1071559748), product.ID)
+	assert.Equal(t, "Black", product.Variants[1].Option1)
+	assert.Equal(t, "M", product.Variants[1].Option2)
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "Color", "values": []interface{}{"Black", "White"}},
+		map[string]interface{}{"name": "Size", "values": []interface{}{"S", "M", "L"}},
+	}, posted["options"])
+	variants := posted["variants"].([]interface{})
+	assert.Equal(t, 6, len(variants))
+	assert.Equal(t, map[string]interface{}{"option1": "Black", "option2": "S", "price": "449.00"}, variants[0])
+	last := variants[5].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"option1": "White", "option2": "L"}, last)
+	for _, field := range []string{"id", "status", "published_scope", "created_at"} {
+		_, sent := posted[field]
+		assert.T(t, !sent, field)
+	}
+	for _, field := range []string{"inventory_policy", "fulfillment_service", "weight_unit", "price", "taxable", "requires_shipping"} {
+		_, sent := last[field]
+		assert.T(t, !sent, field)
+	}
+}
+
+// Should refuse variants that don't match the product's options before calling shopify
+func TestCreateProductInvalidVariants(t *testing.T) {
+	options := BuildProductOptions(map[string][]string{"Size": {"S", "M"}, "Color": {"Black"}})
+
+	_, errs := shop.CreateProduct(Product{Options: options, Variants: []Variant{{Option1: "Black"}}})
+	assert.Equal(t, "variant 0 has 1 option values, the product has 2 options", errs[0].Error())
+
+	_, errs = shop.CreateProduct(Product{Options: options, Variants: []Variant{{Option1: "Black", Option2: "S"}, {Option1: "Black", Option2: "S"}}})
+	assert.Equal(t, `variant 1 repeats the option values ["Black" "S"]`, errs[0].Error())
+
+	_, errs = shop.CreateProduct(Product{Options: make([]map[string]interface{}, 4)})
+	assert.Equal(t, "a product has at most 3 options, got 4", errs[0].Error())
+}
+
+// Should create every valid product and report the ones shopify rejected
+func TestCreateProducts(t *testing.T) {
+	calls := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/admin/products.json", r.URL.Path)
+
+		var body ProductResponse
+		data, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(data, &body)
+		assert.T(t, !strings.Contains(string(data), `"id"`), string(data))
+		assert.T(t, !strings.Contains(string(data), `"status"`), string(data))
+		if body.Product.Title == "" {
+			w.WriteHeader(422)
+			w.Write([]byte(`{"errors":{"title":["can't be blank"]}}`))
+			return
+		}
+		body.Product.ID = int64(1000 + calls)
+		w.WriteHeader(201)
+		json.NewEncoder(w).Encode(body)
+	})
+	defer server.Close()
+
+	results, errs := mock.CreateProducts([]Product{{Title: "Burton Custom"}, {}, {Title: "Burton Feelgood"}})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 3, len(results))
+	for i, result := range results {
+		assert.Equal(t, i, result.Index)
+	}
+
+	assert.T(t, results[0].Error == nil)
+	assert.Equal(t, "Burton Custom", results[0].Product.Title)
+	assert.Equal(t, int64(1001), results[0].Product.ID)
+
+	assert.T(t, results[1].Product == nil)
+	assert.Equal(t, 422, results[1].Error.StatusCode)
+
+	assert.T(t, results[2].Error == nil)
+	assert.Equal(t, int64(1003), results[2].Product.ID)
+}
+
+func diffTestProduct() Product {
+	return Product{
+		ID:     632910392,
+		Title:  "IPod Nano - 8GB",
+		Vendor: "Apple",
+		Variants: []Variant{
+			{ID: 808950810, ProductID: 632910392, Title: "Pink", Price: Money{Amount: "199.00"}, SKU: "IPOD2008PINK"},
+			{ID: 49148385, ProductID: 632910392, Title: "Red", Price: Money{Amount: "199.00"}, SKU: "IPOD2008RED"},
+		},
+	}
+}
+
+// Should only send the title when nothing else changed
+func TestProductUpdateDiffTitle(t *testing.T) {
+	current := diffTestProduct()
+	desired := diffTestProduct()
+	desired.Title = "IPod Nano - 16GB"
+
+	assert.Equal(t, map[string]interface{}{"title": "IPod Nano - 16GB"}, ProductUpdateDiff(current, desired))
+	assert.Equal(t, 0, len(ProductUpdateDiff(current, current)))
+}
+
+// Should send the changed variant fields by id and keep the other variants
+func TestProductUpdateDiffVariantPrice(t *testing.T) {
+	current := diffTestProduct()
+	desired := diffTestProduct()
+	desired.Variants[1].Price = Money{Amount: "249.00"}
+
+	diff := ProductUpdateDiff(current, desired)
+
+	assert.Equal(t, map[string]interface{}{
+		"variants": []map[string]interface{}{
+			{"id": int64(808950810)},
+			{"id": int64(49148385), "price": "249.00"},
+		},
+	}, diff)
+}
+
+// Should list new variants with their fields and drop the removed ones
+func TestProductUpdateDiffVariantAddRemove(t *testing.T) {
+	current := diffTestProduct()
+	desired := diffTestProduct()
+	desired.Variants = []Variant{desired.Variants[0], {Title: "Blue", Price: Money{Amount: "209.00"}, Option1: "Blue"}}
+
+	diff := ProductUpdateDiff(current, desired)
+
+	assert.Equal(t, map[string]interface{}{
+		"variants": []map[string]interface{}{
+			{"id": int64(808950810)},
+			{"title": "Blue", "price": "209.00", "option1": "Blue"},
+		},
+	}, diff)
+}
+
+// Should PUT the diff to the product
+func TestUpdateProduct(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/admin/products/632910392.json", r.URL.Path)
+
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, map[string]interface{}{"id": float64(632910392), "title": "Edited"}, body["product"])
+
+		w.Write(loadFixture(t, "product.json"))
+	})
+	defer server.Close()
+
+	current := diffTestProduct()
+	desired := diffTestProduct()
+	desired.Title = "Edited"
+	product, errs := mock.UpdateProduct(632910392, ProductUpdateDiff(current, desired))
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, int64(632910392), product.ID)
+}
+
+// productsByHandleHandler answers the handle query with the number of products given for each handle
+func productsByHandleHandler(t *testing.T, matches map[string]int) http.HandlerFunc {
+	product := ProductResponse{}
+	json.Unmarshal(loadFixture(t, "product.json"), &product)
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/products.json", r.URL.Path)
+		handle := r.URL.Query().Get("handle")
+		products := ProductsResponse{Products: []Product{}}
+		for i := 0; i < matches[handle]; i++ {
+			products.Products = append(products.Products, product.Product)
+		}
+		json.NewEncoder(w).Encode(products)
+	}
+}
+
+// Should read the handle and the global SEO metafields of the product
+func TestGetProductSEO(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/products/632910392.json":
+			assert.Equal(t, "id,handle", r.URL.Query().Get("fields"))
+			w.Write([]byte(`{"product": {"id": 632910392, "handle": "ipod-nano"}}`))
+		case "/admin/products/632910392/metafields.json":
+			assert.Equal(t, "global", r.URL.Query().Get("namespace"))
+			w.Write([]byte(`{"metafields": [
+				{"id": 1, "namespace": "global", "key": "title_tag", "value": "iPod Nano - 8GB in black", "type": "single_line_text_field"},
+				{"id": 2, "namespace": "global", "key": "description_tag", "value": "The slimmest iPod yet.", "type": "multi_line_text_field"}
+			]}`))
+		default:
+			t.Errorf("unexpected request to %v", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	seo, errs := mock.GetProductSEO(632910392)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, ProductSEO{Title: "iPod Nano - 8GB in black", Description: "The slimmest iPod yet.", Handle: "ipod-nano"}, seo)
+}
+
+// Should put the global SEO fields of the product, and the handle only when given
+func TestSetProductSEO(t *testing.T) {
+	var bodies []map[string]interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/admin/products/632910392.json", r.URL.Path)
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body["product"])
+		w.Write([]byte(`{"product": {"id": 632910392}}`))
+	})
+	defer server.Close()
+
+	errs := mock.SetProductSEO(632910392, ProductSEO{Title: "iPod Nano", Description: "The slimmest iPod yet.", Handle: "ipod-nano-8gb"})
+	assert.T(t, errs == nil, errs)
+	errs = mock.SetProductSEO(632910392, ProductSEO{Title: "iPod Nano"})
+	assert.T(t, errs == nil, errs)
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"id":                                float64(632910392),
+			"metafields_global_title_tag":       "iPod Nano",
+			"metafields_global_description_tag": "The slimmest iPod yet.",
+			"handle":                            "ipod-nano-8gb",
+		},
+		{
+			"id":                                float64(632910392),
+			"metafields_global_title_tag":       "iPod Nano",
+			"metafields_global_description_tag": "",
+		},
+	}, bodies)
+}
+
+// Should decode the taxonomy node of the product's category
+func TestGetProductCategory(t *testing.T) {
+	var ids []interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		ids = append(ids, body["variables"]["id"])
+		if body["variables"]["id"] == "gid://shopify/Product/632910392" {
+			w.Write([]byte(`{"data": {"product": {"productCategory": {"productTaxonomyNode": {
+				"id": "gid://shopify/ProductTaxonomyNode/352", "name": "Headphones", "fullName": "Electronics > Audio > Headphones"}}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"product": {"productCategory": null}}}`))
+	})
+	defer server.Close()
+
+	category, errs := mock.GetProductCategory(632910392)
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, ProductCategory{ID: "gid://shopify/ProductTaxonomyNode/352", Name: "Headphones", FullName: "Electronics > Audio > Headphones"}, category)
+
+	category, errs = mock.GetProductCategory(921728736)
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, ProductCategory{}, category)
+	assert.Equal(t, []interface{}{"gid://shopify/Product/632910392", "gid://shopify/Product/921728736"}, ids)
+}
+
+// Should return the single product matching the handle
+func TestGetProductByHandle(t *testing.T) {
+	mock, server := newMockShopify(t, productsByHandleHandler(t, map[string]int{"ipod nano & co": 1}))
+	defer server.Close()
+
+	product, errs := mock.GetProductByHandle("ipod nano & co")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, int64(632910392), product.ID)
+}
+
+// Should fail when no product has the handle
+func TestGetProductByHandleNotFound(t *testing.T) {
+	mock, server := newMockShopify(t, productsByHandleHandler(t, nil))
+	defer server.Close()
+
+	product, errs := mock.GetProductByHandle("missing")
+
+	assert.T(t, product == nil)
+	assert.Equal(t, `no product found with handle "missing"`, errs[0].Error())
+}
+
+// Should fail when several products have the handle
+func TestGetProductByHandleAmbiguous(t *testing.T) {
+	mock, server := newMockShopify(t, productsByHandleHandler(t, map[string]int{"ipod-nano": 2}))
+	defer server.Close()
+
+	product, errs := mock.GetProductByHandle("ipod-nano")
+
+	assert.T(t, product == nil)
+	assert.Equal(t, `2 products found with handle "ipod-nano"`, errs[0].Error())
+}
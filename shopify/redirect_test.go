@@ -0,0 +1,68 @@
+package shopify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should follow a 303 chain on the same host keeping the credentials
+func TestRedirectSameHost(t *testing.T) {
+	var mockURL string
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/orders/count.json":
+			http.Redirect(w, r, "/admin/orders/count/step.json", http.StatusSeeOther)
+		case "/admin/orders/count/step.json":
+			http.Redirect(w, r, mockURL+"/admin/orders/count/final.json", http.StatusSeeOther)
+		case "/admin/orders/count/final.json":
+			user, pass, ok := r.BasicAuth()
+			assert.T(t, ok, "credentials should be kept on the same host")
+			assert.Equal(t, "mock-key", user)
+			assert.Equal(t, "mock-pass", pass)
+			w.Write([]byte(`{"count": 3}`))
+		}
+	})
+	defer server.Close()
+	mockURL = server.URL
+
+	count, errs := mock.GetOrdersCount()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 3, count)
+}
+
+// Should not send the credentials when redirected to another host
+func TestRedirectOtherHost(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, ok := r.BasicAuth()
+		assert.T(t, !ok, "credentials leaked to another host")
+		assert.Equal(t, "", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"count": 7}`))
+	}))
+	defer other.Close()
+
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/count.json", http.StatusSeeOther)
+	})
+	defer server.Close()
+
+	count, errs := mock.GetOrdersCount()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 7, count)
+}
+
+// Should give up once the redirect limit is reached
+func TestRedirectLimit(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+	}, WithMaxRedirects(2))
+	defer server.Close()
+
+	_, errs := mock.GetOrdersCount()
+
+	assert.Equal(t, 1, len(errs))
+}
@@ -13,3 +13,66 @@ func (shopify *Shopify) GetOrderRefunds(orderID int64, parameters map[string]str
 	}
 	return refunds.Refunds, nil
 }
+
+// Restock types of a refunded line item
+const (
+	RestockTypeNoRestock = "no_restock"
+	RestockTypeCancel    = "cancel"
+	RestockTypeReturn    = "return"
+)
+
+//RefundLineItems refunds the given line items of an order. Shopify first calculates the refund so that
+//the suggested transactions are refunded to their original gateways, then the refund is created.
+//Line items without a restock type are not restocked.
+func (shopify *Shopify) RefundLineItems(orderID int64, items []RefundLineItem, notify bool) (*Refund, []error) {
+	lineItems := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		restockType := item.RestockType
+		if restockType == "" {
+			restockType = RestockTypeNoRestock
+		}
+		if restockType != RestockTypeNoRestock && restockType != RestockTypeCancel && restockType != RestockTypeReturn {
+			return nil, []error{fmt.Errorf("invalid restock type %q for line item %v", item.RestockType, item.LineItemID)}
+		}
+		lineItem := map[string]interface{}{
+			"line_item_id": item.LineItemID,
+			"quantity":     item.Quantity,
+			"restock_type": restockType,
+		}
+		if item.LocationID != nil {
+			lineItem["location_id"] = *item.LocationID
+		}
+		lineItems = append(lineItems, lineItem)
+	}
+
+	var calculated RefundResponse
+	response, errors := shopify.Post(fmt.Sprintf("orders/%v/refunds/calculate", orderID), map[string]interface{}{
+		"refund": map[string]interface{}{"refund_line_items": lineItems},
+	})
+	if err := unmarshal(response, errors, &calculated); len(err) > 0 {
+		return nil, err
+	}
+
+	transactions := make([]map[string]interface{}, 0, len(calculated.Refund.Transactions))
+	for _, transaction := range calculated.Refund.Transactions {
+		transactions = append(transactions, map[string]interface{}{
+			"parent_id": transaction.ParentID,
+			"amount":    transaction.Amount,
+			"kind":      "refund",
+			"gateway":   transaction.Gateway,
+		})
+	}
+
+	var refundResponse RefundResponse
+	response, errors = shopify.Post(fmt.Sprintf("orders/%v/refunds", orderID), map[string]interface{}{
+		"refund": map[string]interface{}{
+			"notify":            notify,
+			"refund_line_items": lineItems,
+			"transactions":      transactions,
+		},
+	})
+	if err := unmarshal(response, errors, &refundResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &refundResponse.Refund, nil
+}
@@ -0,0 +1,57 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should calculate the refund and then create it with the suggested transactions
+func TestRefundLineItems(t *testing.T) {
+	calculate := loadFixture(t, "refund_calculate.json")
+	var paths []string
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		refund := body["refund"]
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"line_item_id": float64(518995019), "quantity": float64(1), "restock_type": "return", "location_id": float64(487838322)},
+			map[string]interface{}{"line_item_id": float64(703073504), "quantity": float64(2), "restock_type": "no_restock"},
+		}, refund["refund_line_items"])
+
+		if r.URL.Path == "/admin/orders/450789469/refunds/calculate.json" {
+			w.Write(calculate)
+			return
+		}
+		assert.Equal(t, true, refund["notify"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"parent_id": float64(801038806), "amount": "199.65", "kind": "refund", "gateway": "bogus"},
+		}, refund["transactions"])
+		w.WriteHeader(201)
+		w.Write([]byte(`{"refund": {"id": 509562969, "order_id": 450789469}}`))
+	})
+	defer server.Close()
+
+	location := int64(487838322)
+	refund, errs := mock.RefundLineItems(450789469, []RefundLineItem{
+		{LineItemID: 518995019, Quantity: 1, RestockType: RestockTypeReturn, LocationID: &location},
+		{LineItemID: 703073504, Quantity: 2},
+	}, true)
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"/admin/orders/450789469/refunds/calculate.json", "/admin/orders/450789469/refunds.json"}, paths)
+	assert.Equal(t, int64(509562969), refund.ID)
+}
+
+// Should reject an unknown restock type before calling shopify
+func TestRefundLineItemsInvalidRestockType(t *testing.T) {
+	refund, errs := shop.RefundLineItems(450789469, []RefundLineItem{{LineItemID: 1, Quantity: 1, RestockType: "legacy_restock"}}, false)
+
+	assert.T(t, refund == nil)
+	assert.Equal(t, 1, len(errs))
+}
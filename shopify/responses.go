@@ -15,6 +15,11 @@ type OrderResponse struct {
 	Order Order `json:"order"`
 }
 
+//DraftOrderResponse is a response for a draft order
+type DraftOrderResponse struct {
+	DraftOrder DraftOrder `json:"draft_order"`
+}
+
 //TransactionsResponse is a response to /orders/{id}/transactions
 type TransactionsResponse struct {
 	Transactions []Transaction `json:"transactions"`
@@ -24,6 +29,11 @@ type RefundsResponse struct {
 	Refunds []Refund `json:"refunds"`
 }
 
+//RefundResponse is a response for a refund
+type RefundResponse struct {
+	Refund Refund `json:"refund"`
+}
+
 //CountResponse is a response to counts endpoint
 type CountResponse struct {
 	Count int `json:"count"`
@@ -39,12 +49,154 @@ type ProductResponse struct {
 	Product Product `json:"product"`
 }
 
+//FulfillmentServicesResponse is a response to /fulfillment_services endpoint
+type FulfillmentServicesResponse struct {
+	FulfillmentServices []FulfillmentService `json:"fulfillment_services"`
+}
+
 //ImagesResponse is a response for product images
 type ImagesResponse struct {
 	Images []ProductImage `json:"images"`
 }
 
+//VariantResponse is a response for a variant
+type VariantResponse struct {
+	Variant Variant `json:"variant"`
+}
+
 //VariantsResponse is a response for product images
 type VariantsResponse struct {
 	Variants []Variant `json:"variants"`
 }
+
+//WebhooksResponse is a response to /webhooks endpoint
+type WebhooksResponse struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+//WebhookResponse is a response for a webhook
+type WebhookResponse struct {
+	Webhook Webhook `json:"webhook"`
+}
+
+//InventoryItemResponse is a response for an inventory item
+type InventoryItemResponse struct {
+	InventoryItem InventoryItem `json:"inventory_item"`
+}
+
+//InventoryLevelsResponse is a response to /inventory_levels endpoint
+type InventoryLevelsResponse struct {
+	InventoryLevels []InventoryLevel `json:"inventory_levels"`
+}
+
+//PaymentGatewaysResponse is a response to /payment_gateways endpoint
+type PaymentGatewaysResponse struct {
+	PaymentGateways []PaymentGateway `json:"payment_gateways"`
+}
+
+//PoliciesResponse is a response to /policies endpoint
+type PoliciesResponse struct {
+	Policies []Policy `json:"policies"`
+}
+
+//MetafieldsResponse is a response to /metafields endpoint
+type MetafieldsResponse struct {
+	Metafields []Metafield `json:"metafields"`
+}
+
+//MetafieldResponse is a response for a metafield
+type MetafieldResponse struct {
+	Metafield Metafield `json:"metafield"`
+}
+
+//ShopResponse is a response to /shop endpoint
+type ShopResponse struct {
+	Shop Shop `json:"shop"`
+}
+
+//CurrenciesResponse is a response to /currencies endpoint
+type CurrenciesResponse struct {
+	Currencies []Currency `json:"currencies"`
+}
+
+//AccessScopesResponse is a response to /oauth/access_scopes endpoint
+type AccessScopesResponse struct {
+	AccessScopes []struct {
+		Handle string `json:"handle"`
+	} `json:"access_scopes"`
+}
+
+//CustomerResponse is a response for a customer
+type CustomerResponse struct {
+	Customer Customer `json:"customer"`
+}
+
+//CustomersResponse is a response to /customers/search endpoint
+type CustomersResponse struct {
+	Customers []Customer `json:"customers"`
+}
+
+//CustomerAddressesResponse is a response to /customers/{id}/addresses endpoint
+type CustomerAddressesResponse struct {
+	Addresses []CustomerAddress `json:"addresses"`
+}
+
+//CustomerAddressResponse is a response for a customer address
+type CustomerAddressResponse struct {
+	CustomerAddress CustomerAddress `json:"customer_address"`
+}
+
+//AbandonedCheckoutsResponse is a response to /checkouts endpoint
+type AbandonedCheckoutsResponse struct {
+	Checkouts []AbandonedCheckout `json:"checkouts"`
+}
+
+//CheckoutResponse is a response for a checkout
+type CheckoutResponse struct {
+	Checkout Checkout `json:"checkout"`
+}
+
+//ShippingRatesResponse is a response to /checkouts/{token}/shipping_rates endpoint
+type ShippingRatesResponse struct {
+	ShippingRates []ShippingRate `json:"shipping_rates"`
+}
+
+//SmartCollectionResponse is a response for a smart collection
+type SmartCollectionResponse struct {
+	SmartCollection SmartCollection `json:"smart_collection"`
+}
+
+//RedirectResponse is a response for a URL redirect
+type RedirectResponse struct {
+	Redirect Redirect `json:"redirect"`
+}
+
+//LocationsResponse is a response to /locations endpoint
+type LocationsResponse struct {
+	Locations []Location `json:"locations"`
+}
+
+//ApplicationChargeResponse is a response for an application charge
+type ApplicationChargeResponse struct {
+	ApplicationCharge ApplicationCharge `json:"application_charge"`
+}
+
+//RecurringApplicationChargeResponse is a response for a recurring application charge
+type RecurringApplicationChargeResponse struct {
+	RecurringApplicationCharge RecurringApplicationCharge `json:"recurring_application_charge"`
+}
+
+//UsageChargeResponse is a response for a usage charge
+type UsageChargeResponse struct {
+	UsageCharge UsageCharge `json:"usage_charge"`
+}
+
+//ThemesResponse is a response to /themes endpoint
+type ThemesResponse struct {
+	Themes []Theme `json:"themes"`
+}
+
+//AssetResponse is a response for a theme asset
+type AssetResponse struct {
+	Asset Asset `json:"asset"`
+}
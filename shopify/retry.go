@@ -0,0 +1,70 @@
+package shopify
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Throttled requests are retried up to 3 times, waiting 0.5s, 1s and 2s unless shopify asks otherwise
+	defaultMaxRetries = 3
+	retryBaseDelay    = 500 * time.Millisecond
+	retryMaxDelay     = 30 * time.Second
+)
+
+// WithMaxRetries Changes how many times a throttled (429) request is retried, 0 disables retries.
+func WithMaxRetries(n int) Option {
+	return func(shopify *Shopify) {
+		shopify.maxRetries = n
+	}
+}
+
+// WithRetryJitter Enables full jitter: each retry waits a random delay between 0 and the computed
+// backoff, so that a fleet of clients throttled together does not retry in lockstep.
+func WithRetryJitter(enabled bool) Option {
+	return func(shopify *Shopify) {
+		shopify.retryJitter = enabled
+	}
+}
+
+// retryDelay Returns how long to wait before retrying attempt, honoring shopify's Retry-After header
+func (shopify *Shopify) retryDelay(attempt int, retryAfter string) time.Duration {
+	if seconds, err := strconv.ParseFloat(retryAfter, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	delay := backoff(attempt)
+	if shopify.retryJitter && shopify.random != nil && delay > 0 {
+		delay = time.Duration(shopify.random.int63n(int64(delay)))
+	}
+	return delay
+}
+
+// backoff Doubles the base delay for every attempt, up to the max delay
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 0; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// lockedRand is a *rand.Rand safe for concurrent use
+type lockedRand struct {
+	mu     sync.Mutex
+	random *rand.Rand
+}
+
+func newLockedRand(seed int64) *lockedRand {
+	return &lockedRand{random: rand.New(rand.NewSource(seed))}
+}
+
+func (r *lockedRand) int63n(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.random.Int63n(n)
+}
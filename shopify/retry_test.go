@@ -0,0 +1,69 @@
+package shopify
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 500*time.Millisecond, backoff(0))
+	assert.Equal(t, time.Second, backoff(1))
+	assert.Equal(t, 2*time.Second, backoff(2))
+	assert.Equal(t, retryMaxDelay, backoff(20))
+}
+
+// Should draw every jittered delay between 0 and the attempt's backoff
+func TestRetryDelayJitter(t *testing.T) {
+	client := New("mystore", "key", "pass", WithRetryJitter(true))
+	client.random = newLockedRand(42)
+
+	jittered := false
+	for attempt := 0; attempt < 8; attempt++ {
+		delay := client.retryDelay(attempt, "")
+		assert.T(t, delay >= 0 && delay < backoff(attempt), attempt, delay)
+		jittered = jittered || delay != backoff(attempt)
+	}
+	assert.T(t, jittered)
+
+	// the same seed draws the same delays
+	other := New("mystore", "key", "pass", WithRetryJitter(true))
+	other.random = newLockedRand(42)
+	client.random = newLockedRand(42)
+	for attempt := 0; attempt < 8; attempt++ {
+		assert.Equal(t, client.retryDelay(attempt, ""), other.retryDelay(attempt, ""))
+	}
+}
+
+// Should wait exactly the backoff without jitter, or what shopify asked for
+func TestRetryDelay(t *testing.T) {
+	client := New("mystore", "key", "pass")
+
+	assert.Equal(t, time.Second, client.retryDelay(1, ""))
+	assert.Equal(t, 2*time.Second, client.retryDelay(0, "2.0"))
+	assert.Equal(t, time.Duration(0), client.retryDelay(3, "0"))
+}
+
+// Should retry a throttled request
+func TestRetryThrottled(t *testing.T) {
+	calls := 0
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"errors":"Exceeded 2 calls per second for api client. Reduce request rates to resume uninterrupted service."}`))
+			return
+		}
+		w.Write([]byte(`{"count": 5}`))
+	})
+	defer server.Close()
+
+	count, errs := mock.GetOrdersCount()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 5, count)
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,140 @@
+package shopify
+
+import (
+	"fmt"
+	"strings"
+)
+
+const returnCreateMutation = `mutation($returnInput: ReturnInput!) {
+  returnCreate(returnInput: $returnInput) {
+    return {
+      id name status
+      order { id }
+      returnLineItems(first: 250) {
+        edges { node { ... on ReturnLineItem { id quantity returnReason returnReasonNote fulfillmentLineItem { id } } } }
+      }
+    }
+    userErrors { field message }
+  }
+}`
+
+//ReturnReason is the reason a customer gives for returning an item
+type ReturnReason string
+
+//The return reasons accepted by shopify
+const (
+	ReturnReasonColor          ReturnReason = "COLOR"
+	ReturnReasonDefective      ReturnReason = "DEFECTIVE"
+	ReturnReasonNotAsDescribed ReturnReason = "NOT_AS_DESCRIBED"
+	ReturnReasonOther          ReturnReason = "OTHER"
+	ReturnReasonSizeTooLarge   ReturnReason = "SIZE_TOO_LARGE"
+	ReturnReasonSizeTooSmall   ReturnReason = "SIZE_TOO_SMALL"
+	ReturnReasonStyle          ReturnReason = "STYLE"
+	ReturnReasonUnknown        ReturnReason = "UNKNOWN"
+	ReturnReasonUnwanted       ReturnReason = "UNWANTED"
+	ReturnReasonWrongItem      ReturnReason = "WRONG_ITEM"
+)
+
+//ReturnLineItem is a quantity of a fulfilled line item sent back by the customer
+type ReturnLineItem struct {
+	// ID is only known once the return is created
+	ID string
+	// FulfillmentLineItemID is the global id of the fulfilled line item being returned
+	FulfillmentLineItemID string
+	Quantity              int
+	Reason                ReturnReason
+	// ReasonNote details the reason, shopify requires it for ReturnReasonOther
+	ReasonNote string
+}
+
+//Return is a return of some of the fulfilled items of an order
+type Return struct {
+	ID   string
+	Name string
+	// Status is one of REQUESTED, OPEN, CLOSED, DECLINED or CANCELED
+	Status    string
+	OrderID   string
+	LineItems []ReturnLineItem
+}
+
+// graphQLReturn is a return as returned by the GraphQL api
+type graphQLReturn struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Order  struct {
+		ID string `json:"id"`
+	} `json:"order"`
+	ReturnLineItems struct {
+		Edges []struct {
+			Node struct {
+				ID                  string       `json:"id"`
+				Quantity            int          `json:"quantity"`
+				ReturnReason        ReturnReason `json:"returnReason"`
+				ReturnReasonNote    string       `json:"returnReasonNote"`
+				FulfillmentLineItem struct {
+					ID string `json:"id"`
+				} `json:"fulfillmentLineItem"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"returnLineItems"`
+}
+
+// toReturn flattens the line items connection of the return
+func (returned graphQLReturn) toReturn() Return {
+	created := Return{ID: returned.ID, Name: returned.Name, Status: returned.Status, OrderID: returned.Order.ID}
+	for _, edge := range returned.ReturnLineItems.Edges {
+		created.LineItems = append(created.LineItems, ReturnLineItem{
+			ID:                    edge.Node.ID,
+			FulfillmentLineItemID: edge.Node.FulfillmentLineItem.ID,
+			Quantity:              edge.Node.Quantity,
+			Reason:                edge.Node.ReturnReason,
+			ReasonNote:            edge.Node.ReturnReasonNote,
+		})
+	}
+	return created
+}
+
+//CreateReturn opens a return for the given fulfilled items of the order, given by its global id
+//e.g. GlobalID("Order", orderID). Items shopify can't return, e.g. more than were fulfilled, are reported
+//in its userErrors.
+func (shop *Shopify) CreateReturn(orderGID string, items []ReturnLineItem) (Return, []error) {
+	if !strings.HasPrefix(orderGID, "gid://shopify/Order/") {
+		return Return{}, []error{fmt.Errorf("invalid order global id %q", orderGID)}
+	}
+	if len(items) == 0 {
+		return Return{}, []error{fmt.Errorf("a return needs at least one line item")}
+	}
+	lineItems := make([]map[string]interface{}, 0, len(items))
+	for i, item := range items {
+		if item.FulfillmentLineItemID == "" || item.Quantity < 1 {
+			return Return{}, []error{fmt.Errorf("line item %d needs a fulfillment line item id and a positive quantity", i)}
+		}
+		reason := item.Reason
+		if reason == "" {
+			reason = ReturnReasonUnknown
+		}
+		lineItem := map[string]interface{}{
+			"fulfillmentLineItemId": item.FulfillmentLineItemID,
+			"quantity":              item.Quantity,
+			"returnReason":          reason,
+		}
+		if item.ReasonNote != "" {
+			lineItem["returnReasonNote"] = item.ReasonNote
+		}
+		lineItems = append(lineItems, lineItem)
+	}
+	var data struct {
+		ReturnCreate struct {
+			Return *graphQLReturn `json:"return"`
+		} `json:"returnCreate"`
+	}
+	variables := map[string]interface{}{"returnInput": map[string]interface{}{"orderId": orderGID, "returnLineItems": lineItems}}
+	if errs := shop.graphQL(returnCreateMutation, variables, &data); len(errs) > 0 {
+		return Return{}, errs
+	}
+	if data.ReturnCreate.Return == nil {
+		return Return{}, []error{fmt.Errorf("no return created for order %v", orderGID)}
+	}
+	return data.ReturnCreate.Return.toReturn(), nil
+}
@@ -0,0 +1,81 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should send the items with their reasons and decode the created return
+func TestCreateReturn(t *testing.T) {
+	var variables map[string]interface{}
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		variables = body["variables"]
+		w.Write([]byte(`{"data": {"returnCreate": {"return": {
+			"id": "gid://shopify/Return/945000954", "name": "#1001-R1", "status": "OPEN",
+			"order": {"id": "gid://shopify/Order/450789469"},
+			"returnLineItems": {"edges": [
+				{"node": {"id": "gid://shopify/ReturnLineItem/677614678", "quantity": 1, "returnReason": "SIZE_TOO_SMALL",
+					"returnReasonNote": "", "fulfillmentLineItem": {"id": "gid://shopify/FulfillmentLineItem/361389718"}}},
+				{"node": {"id": "gid://shopify/ReturnLineItem/677614679", "quantity": 2, "returnReason": "OTHER",
+					"returnReasonNote": "Arrived scratched", "fulfillmentLineItem": {"id": "gid://shopify/FulfillmentLineItem/361389719"}}}
+			]}
+		}, "userErrors": []}}}`))
+	})
+	defer server.Close()
+
+	created, errs := mock.CreateReturn("gid://shopify/Order/450789469", []ReturnLineItem{
+		{FulfillmentLineItemID: "gid://shopify/FulfillmentLineItem/361389718", Quantity: 1, Reason: ReturnReasonSizeTooSmall},
+		{FulfillmentLineItemID: "gid://shopify/FulfillmentLineItem/361389719", Quantity: 2, Reason: ReturnReasonOther, ReasonNote: "Arrived scratched"},
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, map[string]interface{}{"returnInput": map[string]interface{}{
+		"orderId": "gid://shopify/Order/450789469",
+		"returnLineItems": []interface{}{
+			map[string]interface{}{"fulfillmentLineItemId": "gid://shopify/FulfillmentLineItem/361389718", "quantity": float64(1), "returnReason": "SIZE_TOO_SMALL"},
+			map[string]interface{}{"fulfillmentLineItemId": "gid://shopify/FulfillmentLineItem/361389719", "quantity": float64(2), "returnReason": "OTHER",
+				"returnReasonNote": "Arrived scratched"},
+		},
+	}}, variables)
+	assert.Equal(t, "gid://shopify/Return/945000954", created.ID)
+	assert.Equal(t, "OPEN", created.Status)
+	assert.Equal(t, "gid://shopify/Order/450789469", created.OrderID)
+	assert.Equal(t, []ReturnLineItem{
+		{ID: "gid://shopify/ReturnLineItem/677614678", FulfillmentLineItemID: "gid://shopify/FulfillmentLineItem/361389718",
+			Quantity: 1, Reason: ReturnReasonSizeTooSmall},
+		{ID: "gid://shopify/ReturnLineItem/677614679", FulfillmentLineItemID: "gid://shopify/FulfillmentLineItem/361389719",
+			Quantity: 2, Reason: ReturnReasonOther, ReasonNote: "Arrived scratched"},
+	}, created.LineItems)
+}
+
+// Should surface the userErrors of the mutation
+func TestCreateReturnUserErrors(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"returnCreate": {"return": null, "userErrors": [
+			{"field": ["returnInput", "returnLineItems", "0", "quantity"], "message": "Quantity is not available for return."}
+		]}}}`))
+	})
+	defer server.Close()
+
+	_, errs := mock.CreateReturn("gid://shopify/Order/450789469", []ReturnLineItem{
+		{FulfillmentLineItemID: "gid://shopify/FulfillmentLineItem/361389718", Quantity: 5, Reason: ReturnReasonUnwanted},
+	})
+
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, ErrorMessages{"returnInput.returnLineItems.0.quantity": {"Quantity is not available for return."}},
+		findShopifyError(errs).Errors)
+}
+
+// Should reject an invalid order id and items without a fulfillment line item before calling shopify
+func TestCreateReturnInvalid(t *testing.T) {
+	_, errs := shop.CreateReturn("450789469", []ReturnLineItem{{FulfillmentLineItemID: "gid://shopify/FulfillmentLineItem/1", Quantity: 1}})
+	assert.Equal(t, 1, len(errs))
+
+	_, errs = shop.CreateReturn("gid://shopify/Order/450789469", []ReturnLineItem{{Quantity: 1}})
+	assert.Equal(t, 1, len(errs))
+}
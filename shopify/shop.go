@@ -0,0 +1,120 @@
+package shopify
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const shopLocalesQuery = `{
+  shopLocales { locale name primary published }
+}`
+
+//GetShop returns the store's configuration
+func (shop *Shopify) GetShop() (*Shop, []error) {
+	var shopResponse ShopResponse
+	response, errors := shop.Get("shop")
+	if err := unmarshal(response, errors, &shopResponse); len(err) > 0 {
+		return nil, err
+	}
+	shop.details.mu.Lock()
+	defer shop.details.mu.Unlock()
+	// stores without a known timezone count dates in UTC, which is cached as well so it is not fetched again
+	shop.details.location = time.UTC
+	if location, err := time.LoadLocation(shopResponse.Shop.IANATimezone); err == nil && shopResponse.Shop.IANATimezone != "" {
+		shop.details.location = location
+	}
+	shop.details.primaryDomain = shopResponse.Shop.Domain
+	return &shopResponse.Shop, nil
+}
+
+//ProductURL returns the storefront URL of a product given its handle.
+//It uses the store's primary domain once GetShop was called, its myshopify.com domain otherwise.
+func (shop *Shopify) ProductURL(handle string) string {
+	return shop.storefrontURL("products", handle)
+}
+
+//CollectionURL returns the storefront URL of a collection given its handle
+func (shop *Shopify) CollectionURL(handle string) string {
+	return shop.storefrontURL("collections", handle)
+}
+
+func (shop *Shopify) storefrontURL(resource, handle string) string {
+	host := shop.host
+	shop.details.mu.Lock()
+	if shop.details.primaryDomain != "" {
+		host = shop.details.primaryDomain
+	}
+	shop.details.mu.Unlock()
+	return fmt.Sprintf("%s://%s/%s/%s", shop.scheme, host, resource, url.PathEscape(handle))
+}
+
+//GetCurrencies returns the presentment currencies of a multi-currency store
+func (shop *Shopify) GetCurrencies() ([]Currency, []error) {
+	var currencies CurrenciesResponse
+	response, errors := shop.Get("currencies")
+	if err := unmarshal(response, errors, &currencies); len(err) > 0 {
+		return nil, err
+	}
+	return currencies.Currencies, nil
+}
+
+//ShopLocale is a language enabled on the store
+type ShopLocale struct {
+	// Locale is the ISO code of the language, e.g. "en" or "pt-BR"
+	Locale string `json:"locale"`
+	Name   string `json:"name"`
+	// Primary is set on the default language of the store
+	Primary bool `json:"primary"`
+	// Published is set when customers can see the store in the language
+	Published bool `json:"published"`
+}
+
+//GetShopLocales returns the languages enabled on the store, published or not, so that translation tooling
+//knows which locales to translate to
+func (shop *Shopify) GetShopLocales() ([]ShopLocale, []error) {
+	var data struct {
+		ShopLocales []ShopLocale `json:"shopLocales"`
+	}
+	if errs := shop.graphQL(shopLocalesQuery, nil, &data); len(errs) > 0 {
+		return nil, errs
+	}
+	return data.ShopLocales, nil
+}
+
+//GetPaymentGateways returns the payment providers set up on the store, enabled or not,
+//so that checkout logic can tell which payment methods are active
+func (shop *Shopify) GetPaymentGateways() ([]PaymentGateway, []error) {
+	var gateways PaymentGatewaysResponse
+	response, errors := shop.Get("payment_gateways")
+	if err := unmarshal(response, errors, &gateways); len(err) > 0 {
+		return nil, err
+	}
+	return gateways.PaymentGateways, nil
+}
+
+// shopDetails keeps what GetShop learnt about the store, shared by the copies of the store API object
+type shopDetails struct {
+	mu            sync.Mutex
+	location      *time.Location
+	primaryDomain string
+}
+
+// cachedTimezone returns the store's timezone if GetShop already fetched it
+func (shop *Shopify) cachedTimezone() *time.Location {
+	shop.details.mu.Lock()
+	defer shop.details.mu.Unlock()
+	return shop.details.location
+}
+
+// timezone returns the store's timezone, fetching the shop the first time
+func (shop *Shopify) timezone() (*time.Location, []error) {
+	if location := shop.cachedTimezone(); location != nil {
+		return location, nil
+	}
+	if _, errs := shop.GetShop(); len(errs) > 0 {
+		return nil, errs
+	}
+	return shop.cachedTimezone(), nil
+}
@@ -0,0 +1,43 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+//ShopTime is a timestamp sent by shopify in RFC3339 with the shop's offset, e.g. 2024-01-15T10:30:00-05:00.
+//It embeds the parsed time.Time, keeping the offset; a null or empty timestamp decodes to the zero time.
+type ShopTime struct {
+	time.Time
+}
+
+//UnmarshalJSON parses an RFC3339 timestamp, null and "" leave it zero
+func (shopTime *ShopTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		shopTime.Time = time.Time{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == "" {
+		shopTime.Time = time.Time{}
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return err
+	}
+	shopTime.Time = parsed
+	return nil
+}
+
+//MarshalJSON encodes the timestamp in RFC3339, the zero time as null
+func (shopTime ShopTime) MarshalJSON() ([]byte, error) {
+	if shopTime.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(shopTime.Format(time.RFC3339))
+}
@@ -0,0 +1,72 @@
+package shopify
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should keep the offset of the timestamp
+func TestShopTimeUnmarshalOffset(t *testing.T) {
+	var order Order
+	err := json.Unmarshal([]byte(`{"created_at": "2024-01-15T10:30:00-05:00", "updated_at": "2024-01-16T08:00:00-05:00"}`), &order)
+
+	assert.T(t, err == nil, err)
+	assert.T(t, order.CreatedAt.Equal(time.Date(2024, 1, 15, 15, 30, 0, 0, time.UTC)))
+	_, offset := order.CreatedAt.Zone()
+	assert.Equal(t, -5*3600, offset)
+	assert.Equal(t, 10, order.CreatedAt.Hour())
+	assert.T(t, order.UpdatedAt.Equal(time.Date(2024, 1, 16, 13, 0, 0, 0, time.UTC)))
+}
+
+// Should decode the timestamps of a product and of its images
+func TestShopTimeUnmarshalProduct(t *testing.T) {
+	var product Product
+	err := json.Unmarshal([]byte(`{"id": 632910392, "created_at": "2024-01-15T10:30:00-05:00", "updated_at": "2024-01-16T08:00:00-05:00",
+		"images": [{"id": 850703190, "product_id": 632910392, "position": 1,
+			"created_at": "2024-01-15T10:31:00-05:00", "updated_at": "2024-01-16T08:01:00-05:00"}]}`), &product)
+
+	assert.T(t, err == nil, err)
+	assert.T(t, product.CreatedAt.Equal(time.Date(2024, 1, 15, 15, 30, 0, 0, time.UTC)))
+	assert.T(t, product.UpdatedAt.Equal(time.Date(2024, 1, 16, 13, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 1, len(product.Images))
+	image := product.Images[0]
+	assert.Equal(t, int64(850703190), image.ID)
+	assert.T(t, image.CreatedAt.Equal(time.Date(2024, 1, 15, 15, 31, 0, 0, time.UTC)))
+	assert.T(t, image.UpdatedAt.Equal(time.Date(2024, 1, 16, 13, 1, 0, 0, time.UTC)))
+}
+
+// Should decode the timestamps of a customer
+func TestShopTimeUnmarshalCustomer(t *testing.T) {
+	var customer Customer
+	err := json.Unmarshal([]byte(`{"created_at": "2024-01-15T10:30:00-05:00", "updated_at": "2024-01-16T08:00:00-05:00"}`), &customer)
+
+	assert.T(t, err == nil, err)
+	assert.T(t, customer.CreatedAt.Equal(time.Date(2024, 1, 15, 15, 30, 0, 0, time.UTC)))
+	assert.T(t, customer.UpdatedAt.Equal(time.Date(2024, 1, 16, 13, 0, 0, 0, time.UTC)))
+}
+
+// Should decode null and empty timestamps to the zero time
+func TestShopTimeUnmarshalNull(t *testing.T) {
+	var order Order
+	err := json.Unmarshal([]byte(`{"created_at": null, "updated_at": ""}`), &order)
+
+	assert.T(t, err == nil, err)
+	assert.T(t, order.CreatedAt.IsZero())
+	assert.T(t, order.UpdatedAt.IsZero())
+
+	err = json.Unmarshal([]byte(`{"created_at": "yesterday"}`), &order)
+	assert.T(t, err != nil)
+}
+
+// Should encode the timestamp in RFC3339 and the zero time as null
+func TestShopTimeMarshal(t *testing.T) {
+	created := ShopTime{time.Date(2024, 1, 15, 10, 30, 0, 0, time.FixedZone("EST", -5*3600))}
+
+	data, err := json.Marshal(map[string]ShopTime{"created_at": created, "updated_at": {}})
+
+	assert.T(t, err == nil, err)
+	assert.Equal(t, `{"created_at":"2024-01-15T10:30:00-05:00","updated_at":null}`, string(data))
+}
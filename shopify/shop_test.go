@@ -0,0 +1,88 @@
+package shopify
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should decode the shop and remember its timezone and primary domain, also for bound copies
+func TestGetShop(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/shop.json", "shop.json"))
+	defer server.Close()
+
+	shop, errs := mock.GetShop()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, "apple.myshopify.com", shop.MyshopifyDomain)
+	assert.Equal(t, "America/New_York", mock.cachedTimezone().String())
+	assert.Equal(t, "http://shop.apple.com/products/ipod-nano", mock.WithContext(context.Background()).ProductURL("ipod-nano"))
+}
+
+// Should decode the enabled presentment currencies
+func TestGetCurrencies(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/currencies.json", "currencies.json"))
+	defer server.Close()
+
+	currencies, errs := mock.GetCurrencies()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 3, len(currencies))
+	assert.Equal(t, "EUR", currencies[1].Currency)
+	assert.T(t, currencies[1].Enabled)
+	assert.T(t, !currencies[2].Enabled)
+	assert.Equal(t, time.Date(2018, 1, 24, 0, 1, 1, 0, time.UTC), currencies[0].RateUpdatedAt.UTC())
+}
+
+// Should decode the payment gateways along with their enabled status
+func TestGetPaymentGateways(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/payment_gateways.json", "payment_gateways.json"))
+	defer server.Close()
+
+	gateways, errs := mock.GetPaymentGateways()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(gateways))
+	assert.Equal(t, "shopify_payments", gateways[0].Name)
+	assert.Equal(t, int64(87), gateways[0].ProviderID)
+	assert.T(t, gateways[0].Enabled)
+	assert.Equal(t, "paypal", gateways[1].Name)
+	assert.T(t, !gateways[1].Enabled)
+}
+
+// Should decode the locales with their primary and published flags
+func TestGetShopLocales(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/api/graphql.json", r.URL.Path)
+		w.Write([]byte(`{"data": {"shopLocales": [
+			{"locale": "en", "name": "English", "primary": true, "published": true},
+			{"locale": "fr", "name": "French", "primary": false, "published": true},
+			{"locale": "pt-BR", "name": "Portuguese (Brazil)", "primary": false, "published": false}
+		]}}`))
+	})
+	defer server.Close()
+
+	locales, errs := mock.GetShopLocales()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []ShopLocale{
+		{Locale: "en", Name: "English", Primary: true, Published: true},
+		{Locale: "fr", Name: "French", Published: true},
+		{Locale: "pt-BR", Name: "Portuguese (Brazil)"},
+	}, locales)
+}
+
+// Should build storefront URLs on the myshopify domain and then on the primary one
+func TestProductAndCollectionURL(t *testing.T) {
+	client := New("apple", "key", "pass")
+
+	assert.Equal(t, "https://apple.myshopify.com/products/ipod-nano", client.ProductURL("ipod-nano"))
+	assert.Equal(t, "https://apple.myshopify.com/collections/summer%20sale", client.CollectionURL("summer sale"))
+
+	client.details.primaryDomain = "shop.apple.com"
+	assert.Equal(t, "https://shop.apple.com/products/ipod-nano", client.ProductURL("ipod-nano"))
+	assert.Equal(t, "https://shop.apple.com/collections/frontpage", client.CollectionURL("frontpage"))
+}
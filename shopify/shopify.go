@@ -2,7 +2,13 @@
 package shopify
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/parnurzeal/gorequest"
 )
@@ -15,16 +21,126 @@ type Shopify struct {
 	apiKey string
 	// Store password
 	pass string
+	// Scheme and host used to reach the store, defaults to https and {store}.myshopify.com
+	scheme string
+	host   string
+	// Path prefix of the admin endpoints, defaults to admin
+	adminPrefix string
+	// Paces the requests sent to the store
+	limiter *limiter
+	// User-Agent sent with every request
+	userAgent string
+	// Redirects followed before giving up on a request
+	maxRedirects int
+	// Retries of throttled requests, optionally with a full jitter drawn from random
+	maxRetries  int
+	retryJitter bool
+	random      *lockedRand
+	// Source of time for the limiter and the retries
+	clock clock
+	// Largest limit accepted by each list endpoint, when it differs from the default
+	maxLimits map[string]int
+	// Timezone and primary domain of the store, once fetched
+	details *shopDetails
+	// Cost reported by the last GraphQL query
+	graphQLCost *graphQLCost
+	// Body of the last response, kept only with WithCaptureRawBodies
+	rawBody *rawBody
+	// Context checked by operations spanning several requests
+	ctx context.Context
 }
 
 const (
-	domain = ".myshopify.com/admin"
+	// Version of the library, sent in the default User-Agent
+	Version = "0.2.0"
+
+	domain = ".myshopify.com"
+	// Redirects followed by default, same as net/http
+	defaultMaxRedirects = 10
+	// Path prefixes of the admin, the oauth and the storefront recommendations endpoints
+	adminPrefix           = "admin"
+	oauthPrefix           = "admin/oauth"
+	recommendationsPrefix = "recommendations"
 )
 
+// Option Configures a Shopify Store API object on creation.
+type Option func(*Shopify)
+
+// WithAdminPrefix Changes the path prefix of the admin endpoints, e.g. "admin/api/2019-10".
+// OAuth endpoints are not affected.
+func WithAdminPrefix(prefix string) Option {
+	return func(shopify *Shopify) {
+		shopify.adminPrefix = strings.Trim(prefix, "/")
+	}
+}
+
+// WithUserAgent Replaces the default go-shopify/{version} User-Agent, e.g. to identify your app in shopify's logs.
+func WithUserAgent(userAgent string) Option {
+	return func(shopify *Shopify) {
+		shopify.userAgent = userAgent
+	}
+}
+
+// WithMaxRedirects Changes how many redirects are followed before a request fails, 0 disables them.
+func WithMaxRedirects(n int) Option {
+	return func(shopify *Shopify) {
+		shopify.maxRedirects = n
+	}
+}
+
+// WithCaptureRawBodies Keeps the body of the last response received, available through LastRawBody,
+// to debug a typed result with surprising values.
+func WithCaptureRawBodies() Option {
+	return func(shopify *Shopify) {
+		shopify.rawBody = &rawBody{}
+	}
+}
+
 // New Creates a New Shopify Store API object with the store, apiKey and pass of your store.
 // Usage: shopify.New("mystore", "XXX","YYY")
-func New(store, apiKey, pass string) Shopify {
-	return Shopify{store: store, apiKey: apiKey, pass: pass}
+func New(store, apiKey, pass string, options ...Option) Shopify {
+	shopify := Shopify{store: store, apiKey: apiKey, pass: pass, scheme: "https", host: store + domain,
+		adminPrefix: adminPrefix, userAgent: "go-shopify/" + Version, maxRedirects: defaultMaxRedirects, maxRetries: defaultMaxRetries,
+		random: newLockedRand(time.Now().UnixNano()), clock: realClock{}, limiter: newLimiter(bucketSize, leakRate, realClock{}), graphQLCost: &graphQLCost{}, details: &shopDetails{}}
+	for _, option := range options {
+		option(&shopify)
+	}
+	return shopify
+}
+
+// WithContext Returns a copy of the store API object bound to ctx.
+// Operations spanning several requests, like StreamOrders, stop between requests once ctx is done.
+// Usage: shopify.WithContext(ctx).StreamOrders(nil, fn)
+func (shopify *Shopify) WithContext(ctx context.Context) *Shopify {
+	bound := *shopify
+	bound.ctx = ctx
+	return &bound
+}
+
+// rawBody keeps the body of the last response, shared by the copies of the store API object
+type rawBody struct {
+	mu   sync.Mutex
+	body []byte
+}
+
+// LastRawBody Returns the body of the last response received, nil unless the store API object
+// was created WithCaptureRawBodies.
+// Usage: product, _ := shopify.GetProduct(id); fmt.Println(string(shopify.LastRawBody()))
+func (shopify *Shopify) LastRawBody() []byte {
+	if shopify.rawBody == nil {
+		return nil
+	}
+	shopify.rawBody.mu.Lock()
+	defer shopify.rawBody.mu.Unlock()
+	return shopify.rawBody.body
+}
+
+// context Returns the bound context or the background one
+func (shopify *Shopify) context() context.Context {
+	if shopify.ctx == nil {
+		return context.Background()
+	}
+	return shopify.ctx
 }
 
 // Request Creates a new Request to Shopify and returns the response as a map[string]interface{}.
@@ -33,19 +149,8 @@ func New(store, apiKey, pass string) Shopify {
 // data: content to be sent with the request
 // Usage: shopify.request("GET","products",nil)
 func (shopify *Shopify) Request(method, endpoint string, data interface{}) ([]byte, []error) {
-	jsonData, _ := getJSONBytesFromMap(data)
-	targetURL := shopify.createTargetURL(endpoint)
-
-	request := gorequest.New()
-	request.Get(targetURL)
-
-	if jsonData != nil && data != nil {
-		request.Send(string(jsonData))
-	}
-
-	_, body, errs := request.End()
-
-	return []byte(body), errs
+	_, body, errs := shopify.send(method, shopify.createTargetURL(endpoint), data)
+	return body, errs
 }
 
 // Get Makes a GET request to shopify with the given endpoint.
@@ -58,60 +163,108 @@ func (shopify *Shopify) Get(endpoint string) ([]byte, []error) {
 
 // GetWithParameters Makes a GET request to shopify with the given endpoint and given parameters
 func (shopify *Shopify) GetWithParameters(endpoint string, parameters map[string]string) ([]byte, []error) {
-	targetURL := shopify.createTargetURLWithParameters(endpoint, parameters)
-	request := gorequest.New()
-	_, body, errs := request.Get(targetURL).End()
-
-	return []byte(body), errs
+	_, body, errs := shopify.send("GET", shopify.createTargetURLWithParameters(endpoint, parameters), nil)
+	return body, errs
 }
 
 // Post Makes a POST request to shopify with the given endpoint and data.
 // Usage: shopify.Post("products", map[string]interface{} = product data map)
 func (shopify *Shopify) Post(endpoint string, data interface{}) ([]byte, []error) {
-	targetURL := shopify.createTargetURL(endpoint)
-	jsonData, err := getJSONBytesFromMap(data)
-	if err != nil {
-		return nil, []error{err}
-	}
-
-	request := gorequest.New()
-	request.Post(targetURL)
-	if jsonData != nil && data != nil {
-		request.Send(string(jsonData))
-	}
-	_, body, errs := request.End()
-
-	return []byte(body), errs
+	_, body, errs := shopify.send("POST", shopify.createTargetURL(endpoint), data)
+	return body, errs
 }
 
 // Put Makes a PUT request to shopify with the given endpoint and data.
 // Usage: shopify.Put("products", map[string]interface{} = product data map)
 func (shopify *Shopify) Put(endpoint string, data interface{}) ([]byte, []error) {
-	targetURL := shopify.createTargetURL(endpoint)
-	jsonData, err := getJSONBytesFromMap(data)
-	if err != nil {
-		return nil, []error{err}
-	}
-
-	request := gorequest.New()
-	request.Put(targetURL)
-	if jsonData != nil && data != nil {
-		request.Send(string(jsonData))
-	}
-	_, body, errs := request.End()
-
-	return []byte(body), errs
+	_, body, errs := shopify.send("PUT", shopify.createTargetURL(endpoint), data)
+	return body, errs
 }
 
 // Delete Makes a DELETE request to shopify with the given endpoint.
 // Usage: shopify.Delete("products/5.json")
 func (shopify *Shopify) Delete(endpoint string) ([]byte, []error) {
-	targetURL := shopify.createTargetURL(endpoint)
+	_, body, errs := shopify.send("DELETE", shopify.createTargetURL(endpoint), nil)
+	return body, errs
+}
+
+// send Makes the actual request, waiting for room in the rate limiter first and retrying
+// throttled (429) requests with an exponential backoff.
+// Answers with a non 2xx status code are reported as a *ShopifyError alongside the body, or as a
+// *ScopeError for a 403 naming the missing access scopes.
+func (shopify *Shopify) send(method, targetURL string, data interface{}) (gorequest.Response, []byte, []error) {
+	var jsonData []byte
+	if data != nil {
+		var err error
+		if jsonData, err = getJSONBytesFromMap(data); err != nil {
+			return nil, nil, []error{err}
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if shopify.limiter != nil {
+			shopify.limiter.wait()
+		}
+		response, body, errs := shopify.newRequest(method, targetURL, jsonData).End()
+		if shopify.rawBody != nil {
+			shopify.rawBody.mu.Lock()
+			shopify.rawBody.body = []byte(body)
+			shopify.rawBody.mu.Unlock()
+		}
+		if len(errs) > 0 {
+			return response, []byte(body), errs
+		}
+		if shopify.limiter != nil {
+			shopify.limiter.observe(response.Header.Get(callLimitHeader))
+		}
+		if response.StatusCode == http.StatusTooManyRequests && attempt < shopify.maxRetries {
+			shopify.clock.Sleep(shopify.retryDelay(attempt, response.Header.Get("Retry-After")))
+			continue
+		}
+		if isError, shopifyError := IsErrorResponse(response.StatusCode, []byte(body)); isError {
+			return response, []byte(body), []error{scopeError(shopifyError)}
+		}
+		return response, []byte(body), nil
+	}
+}
 
+// newRequest Prepares a request with the given method, target URL and json body
+func (shopify *Shopify) newRequest(method, targetURL string, jsonData []byte) *gorequest.SuperAgent {
 	request := gorequest.New()
-	_, body, errs := request.Delete(targetURL).End()
+	switch method {
+	case "POST":
+		request.Post(targetURL)
+	case "PUT":
+		request.Put(targetURL)
+	case "DELETE":
+		request.Delete(targetURL)
+	default:
+		request.Get(targetURL)
+	}
+	if jsonData != nil {
+		request.Send(string(jsonData))
+	}
+	if shopify.userAgent != "" {
+		request.Set("User-Agent", shopify.userAgent)
+	}
+	request.RedirectPolicy(shopify.redirectPolicy)
+	return request
+}
 
-	return []byte(body), errs
+// redirectPolicy Follows up to maxRedirects redirects, keeping the credentials on the store's
+// host only so they are never sent to another host.
+func (shopify *Shopify) redirectPolicy(req gorequest.Request, via []gorequest.Request) error {
+	if len(via) >= shopify.maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", len(via))
+	}
+	original := via[0].URL
+	if req.URL.Host != original.Host {
+		req.URL.User = nil
+		req.Header.Del("Authorization")
+	} else if req.URL.User == nil {
+		req.URL.User = original.User
+	}
+	return nil
 }
 
 // Creates target URL for making a Shopify Request to a given endpoint
@@ -121,12 +274,32 @@ func (shopify *Shopify) createTargetURL(endpoint string) string {
 
 // Creates target URL for making a Shopify Request to a given endpoint with the given parameters
 func (shopify *Shopify) createTargetURLWithParameters(endpoint string, parameters map[string]string) string {
+	return shopify.createPrefixedURL(shopify.adminPrefix, endpoint, parameters)
+}
+
+// Creates target URL for making a Shopify Request to a given oauth endpoint, bypassing the admin prefix
+func (shopify *Shopify) createOAuthURL(endpoint string, parameters map[string]string) string {
+	return shopify.createPrefixedURL(oauthPrefix, endpoint, parameters)
+}
+
+// Creates target URL for the GraphQL endpoint, which lives under admin/api when the admin prefix is unversioned
+func (shopify *Shopify) createGraphQLURL() string {
+	prefix := shopify.adminPrefix
+	if prefix == adminPrefix {
+		prefix = adminPrefix + "/api"
+	}
+	return shopify.createPrefixedURL(prefix, "graphql", nil)
+}
+
+// Composes scheme://host/prefix/endpoint.json with the credentials and the given url encoded parameters
+func (shopify *Shopify) createPrefixedURL(prefix, endpoint string, parameters map[string]string) string {
 	var parametersString = ""
-	if parameters != nil && len(parameters) > 0 {
-		parametersString = "?"
-		for k := range parameters {
-			parametersString = fmt.Sprintf("%v%v=%v&", parametersString, k, parameters[k])
+	if len(parameters) > 0 {
+		values := url.Values{}
+		for k, v := range parameters {
+			values.Set(k, v)
 		}
+		parametersString = "?" + values.Encode()
 	}
-	return fmt.Sprintf("https://%s:%s@%s%s/%s.json%s", shopify.apiKey, shopify.pass, shopify.store, domain, endpoint, parametersString)
+	return fmt.Sprintf("%s://%s:%s@%s/%s/%s.json%s", shopify.scheme, shopify.apiKey, shopify.pass, shopify.host, prefix, endpoint, parametersString)
 }
@@ -0,0 +1,95 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+const (
+	// Storefront API version used unless WithStorefrontVersion picks another one
+	defaultStorefrontVersion = "2024-01"
+	// Header carrying the storefront access token
+	storefrontTokenHeader = "X-Shopify-Storefront-Access-Token"
+)
+
+// StorefrontClient queries the Storefront API of a store, the one headless frontends use with
+// a storefront access token instead of the admin credentials.
+type StorefrontClient struct {
+	// Store domain-name
+	store string
+	// Storefront access token
+	token string
+	// Scheme and host used to reach the store, defaults to https and {store}.myshopify.com
+	scheme string
+	host   string
+	// Storefront API version, e.g. "2024-01"
+	version string
+	// User-Agent sent with every request
+	userAgent string
+}
+
+// StorefrontOption Configures a StorefrontClient on creation.
+type StorefrontOption func(*StorefrontClient)
+
+// WithStorefrontVersion Changes the Storefront API version the queries are sent to, e.g. "2023-10".
+func WithStorefrontVersion(version string) StorefrontOption {
+	return func(storefront *StorefrontClient) {
+		storefront.version = strings.Trim(version, "/")
+	}
+}
+
+// NewStorefront Creates a Storefront API client with the store and a storefront access token.
+// Usage: shopify.NewStorefront("mystore", "XXX")
+func NewStorefront(store, storefrontToken string, options ...StorefrontOption) StorefrontClient {
+	storefront := StorefrontClient{store: store, token: storefrontToken, scheme: "https", host: store + domain,
+		version: defaultStorefrontVersion, userAgent: "go-shopify/" + Version}
+	for _, option := range options {
+		option(&storefront)
+	}
+	return storefront
+}
+
+// Query Makes a POST request to the Storefront GraphQL endpoint with the given query and variables.
+// Answers with a non 2xx status code and the top-level errors of the response are returned as a
+// *ShopifyError, along with the body.
+// Usage: storefront.Query("{ shop { name } }", nil)
+func (storefront *StorefrontClient) Query(query string, variables map[string]interface{}) ([]byte, []error) {
+	data := map[string]interface{}{"query": query}
+	if variables != nil {
+		data["variables"] = variables
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	request := gorequest.New().Post(storefront.createGraphQLURL()).
+		Set(storefrontTokenHeader, storefront.token).
+		Send(string(jsonData))
+	if storefront.userAgent != "" {
+		request.Set("User-Agent", storefront.userAgent)
+	}
+	response, body, errs := request.End()
+	if len(errs) > 0 {
+		return []byte(body), errs
+	}
+	if isError, shopifyError := IsErrorResponse(response.StatusCode, []byte(body)); isError {
+		return []byte(body), []error{shopifyError}
+	}
+	return []byte(body), nil
+}
+
+// VerifyToken Checks that the storefront access token is accepted by querying the shop's name.
+// Usage: errs := storefront.VerifyToken()
+func (storefront *StorefrontClient) VerifyToken() []error {
+	_, errs := storefront.Query("{ shop { name } }", nil)
+	return errs
+}
+
+// Creates target URL for the Storefront GraphQL endpoint of the configured version
+func (storefront *StorefrontClient) createGraphQLURL() string {
+	return fmt.Sprintf("%s://%s/api/%s/graphql.json", storefront.scheme, storefront.host, storefront.version)
+}
@@ -0,0 +1,70 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// newMockStorefront creates a StorefrontClient whose requests are answered by handler
+func newMockStorefront(t *testing.T, handler http.HandlerFunc, options ...StorefrontOption) (*StorefrontClient, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+
+	storefront := NewStorefront("mock", "storefront-token", options...)
+	storefront.scheme = serverURL.Scheme
+	storefront.host = serverURL.Host
+	return &storefront, server
+}
+
+// Should post the query to the versioned storefront endpoint with the storefront token
+func TestStorefrontQuery(t *testing.T) {
+	var body map[string]interface{}
+	storefront, server := newMockStorefront(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/2023-10/graphql.json", r.URL.Path)
+		assert.Equal(t, "storefront-token", r.Header.Get("X-Shopify-Storefront-Access-Token"))
+		assert.Equal(t, "", r.Header.Get("Authorization"))
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"data": {"product": {"title": "IPod Nano - 8GB"}}}`))
+	}, WithStorefrontVersion("2023-10"))
+	defer server.Close()
+
+	response, errs := storefront.Query("query($handle: String!) { product(handle: $handle) { title } }",
+		map[string]interface{}{"handle": "ipod-nano"})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, map[string]interface{}{"handle": "ipod-nano"}, body["variables"])
+	var data struct {
+		Data struct {
+			Product struct {
+				Title string `json:"title"`
+			} `json:"product"`
+		} `json:"data"`
+	}
+	assert.T(t, json.Unmarshal(response, &data) == nil)
+	assert.Equal(t, "IPod Nano - 8GB", data.Data.Product.Title)
+}
+
+// Should report a rejected storefront token as a ShopifyError
+func TestStorefrontVerifyToken(t *testing.T) {
+	storefront, server := newMockStorefront(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/"+defaultStorefrontVersion+"/graphql.json", r.URL.Path)
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"errors": "[API] Invalid API key or access token (unrecognized login or wrong password)"}`))
+	})
+	defer server.Close()
+
+	errs := storefront.VerifyToken()
+
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, http.StatusUnauthorized, findShopifyError(errs).StatusCode)
+}
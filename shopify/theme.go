@@ -0,0 +1,65 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// themeSettingsKey is the asset holding the values of the theme settings
+const themeSettingsKey = "config/settings_data.json"
+
+//GetAsset returns a theme asset given its key, e.g. templates/index.liquid.
+//Text assets come in Value, binary ones base64 encoded in Attachment.
+func (shop *Shopify) GetAsset(themeID int64, key string) (*Asset, []error) {
+	var assetResponse AssetResponse
+	response, errors := shop.GetWithParameters(fmt.Sprintf("themes/%v/assets", themeID), map[string]string{"asset[key]": key})
+	if err := unmarshal(response, errors, &assetResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &assetResponse.Asset, nil
+}
+
+//GetThemeSettings returns the parsed config/settings_data.json of a theme, or of the published theme when
+//themeID is 0
+func (shop *Shopify) GetThemeSettings(themeID int64) (map[string]interface{}, []error) {
+	if themeID == 0 {
+		theme, errs := shop.GetMainTheme()
+		if len(errs) > 0 {
+			return nil, errs
+		}
+		themeID = theme.ID
+	}
+	asset, errs := shop.GetAsset(themeID, themeSettingsKey)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal([]byte(asset.Value), &settings); err != nil {
+		return nil, []error{fmt.Errorf("%v of theme %v: %w", themeSettingsKey, themeID, err)}
+	}
+	return settings, nil
+}
+
+//GetThemes returns the themes of the online store
+func (shop *Shopify) GetThemes() ([]Theme, []error) {
+	var themes ThemesResponse
+	response, errors := shop.Get("themes")
+	if err := unmarshal(response, errors, &themes); len(err) > 0 {
+		return nil, err
+	}
+	return themes.Themes, nil
+}
+
+//GetMainTheme returns the published theme, the one with the main role, e.g. to edit its assets
+func (shop *Shopify) GetMainTheme() (*Theme, []error) {
+	themes, errs := shop.GetThemes()
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	for _, theme := range themes {
+		if theme.Role == "main" {
+			return &theme, nil
+		}
+	}
+	return nil, []error{fmt.Errorf("no main theme found among the %d themes of the store", len(themes))}
+}
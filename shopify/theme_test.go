@@ -0,0 +1,57 @@
+package shopify
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should look up the main theme and parse its settings_data.json
+func TestGetThemeSettings(t *testing.T) {
+	routes := routesHandler(t, map[string]string{
+		"/admin/themes.json":                  "themes.json",
+		"/admin/themes/976877075/assets.json": "asset_settings_data.json",
+	})
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/themes/976877075/assets.json" {
+			assert.Equal(t, "config/settings_data.json", r.URL.Query().Get("asset[key]"))
+		}
+		routes(w, r)
+	})
+	defer server.Close()
+
+	settings, errs := mock.GetThemeSettings(0)
+
+	assert.T(t, errs == nil, errs)
+	current := settings["current"].(map[string]interface{})
+	assert.Equal(t, "#ff0000", current["color_primary"])
+	header := current["sections"].(map[string]interface{})["header"].(map[string]interface{})
+	assert.Equal(t, float64(120), header["settings"].(map[string]interface{})["logo_width"])
+}
+
+// Should pick the theme with the main role
+func TestGetMainTheme(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/themes.json", "themes.json"))
+	defer server.Close()
+
+	theme, errs := mock.GetMainTheme()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, int64(976877075), theme.ID)
+	assert.Equal(t, "Speed", theme.Name)
+}
+
+// Should fail when no theme is published
+func TestGetMainThemeNone(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"themes": [{"id": 828155753, "name": "Comfort", "role": "unpublished"}]}`))
+	})
+	defer server.Close()
+
+	theme, errs := mock.GetMainTheme()
+
+	assert.T(t, theme == nil)
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, "no main theme found among the 1 themes of the store", errs[0].Error())
+}
@@ -0,0 +1,35 @@
+package shopify
+
+//RedirectResult is the outcome of creating one of the redirects given to ImportRedirects
+type RedirectResult struct {
+	// Index of the redirect in the input slice
+	Index int
+	// Redirect as created by shopify, nil on failure
+	Redirect *Redirect
+	// Error returned by shopify, e.g. a 422 for a path that already redirects, nil on success
+	Error *ShopifyError
+}
+
+//ImportRedirects creates the given URL redirects one by one, paced by the rate limiter. A redirect
+//rejected by shopify does not stop the import and is reported in its RedirectResult instead.
+func (shop *Shopify) ImportRedirects(redirects []Redirect) ([]RedirectResult, []error) {
+	var errs []error
+	results := make([]RedirectResult, len(redirects))
+	for i, redirect := range redirects {
+		results[i].Index = i
+		var redirectResponse RedirectResponse
+		response, errors := shop.Post("redirects", map[string]interface{}{
+			"redirect": map[string]interface{}{"path": redirect.Path, "target": redirect.Target},
+		})
+		if shopifyError := findShopifyError(errors); shopifyError != nil {
+			results[i].Error = shopifyError
+			continue
+		}
+		if err := unmarshal(response, errors, &redirectResponse); len(err) > 0 {
+			errs = append(errs, err...)
+			continue
+		}
+		results[i].Redirect = &redirectResponse.Redirect
+	}
+	return results, errs
+}
@@ -0,0 +1,47 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should create every redirect and report the duplicate paths shopify rejected
+func TestImportRedirects(t *testing.T) {
+	paths := make(map[string]bool)
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/admin/redirects.json", r.URL.Path)
+
+		var body RedirectResponse
+		json.NewDecoder(r.Body).Decode(&body)
+		if paths[body.Redirect.Path] {
+			w.WriteHeader(422)
+			w.Write([]byte(`{"errors":{"path":["has already been taken"]}}`))
+			return
+		}
+		paths[body.Redirect.Path] = true
+		body.Redirect.ID = int64(len(paths))
+		w.WriteHeader(201)
+		json.NewEncoder(w).Encode(body)
+	})
+	defer server.Close()
+
+	results, errs := mock.ImportRedirects([]Redirect{
+		{Path: "/ipod", Target: "/products/ipod-nano"},
+		{Path: "/ipod", Target: "/products/ipod-touch"},
+		{Path: "/mac", Target: "/collections/mac"},
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, "/products/ipod-nano", results[0].Redirect.Target)
+	assert.T(t, results[0].Error == nil)
+	assert.T(t, results[1].Redirect == nil)
+	assert.Equal(t, 422, results[1].Error.StatusCode)
+	assert.Equal(t, ErrorMessages{"path": {"has already been taken"}}, results[1].Error.Errors)
+	assert.Equal(t, 2, results[2].Index)
+	assert.Equal(t, int64(2), results[2].Redirect.ID)
+}
@@ -0,0 +1,322 @@
+package shopify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const webhookSubscriptionsQuery = `query($after: String) {
+  webhookSubscriptions(first: 100, after: $after) {
+    pageInfo { hasNextPage endCursor }
+    edges {
+      node {
+        id
+        topic
+        format
+        includeFields
+        metafieldNamespaces
+        createdAt
+        updatedAt
+        endpoint {
+          __typename
+          ... on WebhookHttpEndpoint { callbackUrl }
+          ... on WebhookEventBridgeEndpoint { arn }
+          ... on WebhookPubSubEndpoint { pubSubProject pubSubTopic }
+        }
+      }
+    }
+  }
+}`
+
+//WebhookSubscription is a webhook subscription as seen by the GraphQL api, which also lists the
+//subscriptions delivered to EventBridge and Pub/Sub rather than to an HTTP address
+type WebhookSubscription struct {
+	ID string
+	// Topic is in GraphQL form, e.g. ORDERS_CREATE
+	Topic string
+	// Format is JSON or XML
+	Format string
+	// EndpointType is one of WebhookHttpEndpoint, WebhookEventBridgeEndpoint or WebhookPubSubEndpoint
+	EndpointType string
+	// Endpoint is the callback URL, the EventBridge ARN or the Pub/Sub project and topic, as pubsub://project:topic
+	Endpoint            string
+	IncludeFields       []string
+	MetafieldNamespaces []string
+	CreatedAt           ShopTime
+	UpdatedAt           ShopTime
+}
+
+// webhookTopicPattern matches Shopify's resource/action topic format, e.g. "orders/create", whose parts
+// may be dotted as in "customers.tags/update"
+var webhookTopicPattern = regexp.MustCompile(`^[a-z_]+(\.[a-z_]+)*/[a-z_]+(\.[a-z_]+)*$`)
+
+//GetWebhooksByTopic returns the webhooks subscribed to the given topic
+func (shop *Shopify) GetWebhooksByTopic(topic string) ([]Webhook, []error) {
+	if err := validateWebhookTopic(topic); err != nil {
+		return nil, []error{err}
+	}
+	var webhooks WebhooksResponse
+	response, errors := shop.GetWithParameters("webhooks", map[string]string{"topic": topic})
+	if err := unmarshal(response, errors, &webhooks); len(err) > 0 {
+		return nil, err
+	}
+	return webhooks.Webhooks, nil
+}
+
+//GetWebhooks returns the webhooks matching the given parameters, e.g. address or topic
+func (shop *Shopify) GetWebhooks(parameters map[string]string) ([]Webhook, []error) {
+	if err := shop.checkLimit("webhooks", parameters); err != nil {
+		return nil, []error{err}
+	}
+	var webhooks WebhooksResponse
+	response, errors := shop.GetWithParameters("webhooks", parameters)
+	if err := unmarshal(response, errors, &webhooks); len(err) > 0 {
+		return nil, err
+	}
+	return webhooks.Webhooks, nil
+}
+
+// allWebhooks pages through every webhook matching the given filters, e.g. address
+func (shop *Shopify) allWebhooks(filters map[string]string) ([]Webhook, []error) {
+	parameters := map[string]string{"limit": strconv.Itoa(shop.maxLimit("webhooks"))}
+	for name, value := range filters {
+		parameters[name] = value
+	}
+	var webhooks []Webhook
+	errs := shop.paginate("webhooks", parameters, func(page []byte) []error {
+		var webhooksResponse WebhooksResponse
+		if err := unmarshal(page, nil, &webhooksResponse); len(err) > 0 {
+			return err
+		}
+		webhooks = append(webhooks, webhooksResponse.Webhooks...)
+		return nil
+	})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return webhooks, nil
+}
+
+//CreateWebhook subscribes the webhook's address to its topic
+func (shop *Shopify) CreateWebhook(webhook Webhook) (*Webhook, []error) {
+	if err := validateWebhookTopic(webhook.Topic); err != nil {
+		return nil, []error{err}
+	}
+	var webhookResponse WebhookResponse
+	response, errors := shop.Post("webhooks", map[string]interface{}{"webhook": webhookBody(webhook)})
+	if err := unmarshal(response, errors, &webhookResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &webhookResponse.Webhook, nil
+}
+
+//UpdateWebhook updates the address, format and fields of an existing webhook
+func (shop *Shopify) UpdateWebhook(webhook Webhook) (*Webhook, []error) {
+	var webhookResponse WebhookResponse
+	body := webhookBody(webhook)
+	body["id"] = webhook.ID
+	delete(body, "topic")
+	response, errors := shop.Put(fmt.Sprintf("webhooks/%v", webhook.ID), map[string]interface{}{"webhook": body})
+	if err := unmarshal(response, errors, &webhookResponse); len(err) > 0 {
+		return nil, err
+	}
+	return &webhookResponse.Webhook, nil
+}
+
+//DeleteWebhook deletes a webhook given its id
+func (shop *Shopify) DeleteWebhook(webhookID int64) []error {
+	_, errors := shop.Delete(fmt.Sprintf("webhooks/%v", webhookID))
+	return errors
+}
+
+//DeleteWebhooksByAddress deletes every webhook pointing at address, e.g. when the app is uninstalled.
+//A failed delete does not stop the others, all the errors are returned.
+func (shop *Shopify) DeleteWebhooksByAddress(address string) []error {
+	webhooks, errs := shop.allWebhooks(map[string]string{"address": address})
+	if len(errs) > 0 {
+		return errs
+	}
+	for _, webhook := range webhooks {
+		if webhook.Address != address {
+			continue
+		}
+		errs = append(errs, shop.DeleteWebhook(webhook.ID)...)
+	}
+	return errs
+}
+
+//EnsureWebhooks makes sure the desired webhooks exist, matching them to the existing ones by topic and address.
+//Matches are left untouched, a webhook of the same topic pointing elsewhere gets its address updated
+//and the rest are created. It returns the resulting webhooks in the order they were desired.
+func (shop *Shopify) EnsureWebhooks(desired []Webhook) ([]Webhook, []error) {
+	existing, errs := shop.allWebhooks(nil)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	used := make([]bool, len(existing))
+	ensured := make([]Webhook, len(desired))
+	pending := make([]bool, len(desired))
+	for i, webhook := range desired {
+		pending[i] = true
+		for j, current := range existing {
+			if !used[j] && current.Topic == webhook.Topic && current.Address == webhook.Address {
+				used[j], pending[i] = true, false
+				ensured[i] = current
+				break
+			}
+		}
+	}
+
+	for i, webhook := range desired {
+		if !pending[i] {
+			continue
+		}
+		var result *Webhook
+		var err []error
+		for j, current := range existing {
+			if !used[j] && current.Topic == webhook.Topic {
+				used[j] = true
+				current.Address = webhook.Address
+				result, err = shop.UpdateWebhook(current)
+				break
+			}
+		}
+		if result == nil && err == nil {
+			result, err = shop.CreateWebhook(webhook)
+		}
+		if len(err) > 0 {
+			errs = append(errs, err...)
+			continue
+		}
+		ensured[i] = *result
+	}
+	return ensured, errs
+}
+
+//GetWebhookSubscriptions returns every webhook subscription of the app along with its endpoint and format.
+//Shopify doesn't expose the deliveries of a subscription, failures only show in the partner dashboard.
+func (shop *Shopify) GetWebhookSubscriptions() ([]WebhookSubscription, []error) {
+	nodes, errs := shop.PaginateConnection(webhookSubscriptionsQuery, nil, connectionAt("webhookSubscriptions"))
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	var subscriptions []WebhookSubscription
+	for _, node := range nodes {
+		var decoded struct {
+			ID                  string   `json:"id"`
+			Topic               string   `json:"topic"`
+			Format              string   `json:"format"`
+			IncludeFields       []string `json:"includeFields"`
+			MetafieldNamespaces []string `json:"metafieldNamespaces"`
+			CreatedAt           ShopTime `json:"createdAt"`
+			UpdatedAt           ShopTime `json:"updatedAt"`
+			Endpoint            struct {
+				Typename      string `json:"__typename"`
+				CallbackURL   string `json:"callbackUrl"`
+				ARN           string `json:"arn"`
+				PubSubProject string `json:"pubSubProject"`
+				PubSubTopic   string `json:"pubSubTopic"`
+			} `json:"endpoint"`
+		}
+		if err := json.Unmarshal(node, &decoded); err != nil {
+			return nil, []error{err}
+		}
+		subscription := WebhookSubscription{
+			ID:                  decoded.ID,
+			Topic:               decoded.Topic,
+			Format:              decoded.Format,
+			EndpointType:        decoded.Endpoint.Typename,
+			IncludeFields:       decoded.IncludeFields,
+			MetafieldNamespaces: decoded.MetafieldNamespaces,
+			CreatedAt:           decoded.CreatedAt,
+			UpdatedAt:           decoded.UpdatedAt,
+		}
+		switch decoded.Endpoint.Typename {
+		case "WebhookEventBridgeEndpoint":
+			subscription.Endpoint = decoded.Endpoint.ARN
+		case "WebhookPubSubEndpoint":
+			subscription.Endpoint = fmt.Sprintf("pubsub://%s:%s", decoded.Endpoint.PubSubProject, decoded.Endpoint.PubSubTopic)
+		default:
+			subscription.Endpoint = decoded.Endpoint.CallbackURL
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+//VerifyWebhook checks the X-Shopify-Hmac-Sha256 signature of a webhook body against the app's secret
+func VerifyWebhook(body []byte, signature, secret string) bool {
+	expected, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+//ParseWebhook verifies the signature of a webhook request and returns its topic and shop domain headers.
+//A signature that doesn't match the secret is reported with verified set to false, while missing
+//headers are reported as an error.
+func ParseWebhook(headers http.Header, body []byte, secret string) (topic string, shop string, verified bool, err error) {
+	signature := headers.Get("X-Shopify-Hmac-Sha256")
+	topic = headers.Get("X-Shopify-Topic")
+	shop = headers.Get("X-Shopify-Shop-Domain")
+	if signature == "" {
+		return topic, shop, false, fmt.Errorf("missing X-Shopify-Hmac-Sha256 header")
+	}
+	if err := validateWebhookTopic(topic); err != nil {
+		return topic, shop, false, err
+	}
+	if shop == "" {
+		return topic, shop, false, fmt.Errorf("missing X-Shopify-Shop-Domain header")
+	}
+	return topic, shop, VerifyWebhook(body, signature, secret), nil
+}
+
+//HandleAppUninstalled parses the body of an app/uninstalled webhook and returns the myshopify domain
+//of the shop that uninstalled the app, so that the caller can tear down what it keeps for it.
+func HandleAppUninstalled(body []byte) (shop string, err error) {
+	var payload Shop
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("invalid app/uninstalled payload: %v", err)
+	}
+	if payload.ID == 0 || !strings.HasSuffix(payload.MyshopifyDomain, domain) {
+		return "", fmt.Errorf("invalid app/uninstalled payload: missing shop id or myshopify domain")
+	}
+	return payload.MyshopifyDomain, nil
+}
+
+// webhookBody returns the writable fields of a webhook
+func webhookBody(webhook Webhook) map[string]interface{} {
+	body := map[string]interface{}{
+		"topic":   webhook.Topic,
+		"address": webhook.Address,
+		"format":  webhook.Format,
+	}
+	if webhook.Format == "" {
+		body["format"] = "json"
+	}
+	if len(webhook.Fields) > 0 {
+		body["fields"] = webhook.Fields
+	}
+	if len(webhook.MetafieldNamespaces) > 0 {
+		body["metafield_namespaces"] = webhook.MetafieldNamespaces
+	}
+	return body
+}
+
+func validateWebhookTopic(topic string) error {
+	if !webhookTopicPattern.MatchString(topic) {
+		return fmt.Errorf("invalid webhook topic %q, expected resource/action", topic)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package shopify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// Should reject topics that are not in resource/action form before calling shopify
+func TestGetWebhooksByTopicInvalid(t *testing.T) {
+	for _, topic := range []string{"", "orders", "orders/", "/create", "Orders/Create", "orders/create/now", ".orders/create", "orders./create"} {
+		webhooks, errs := shop.GetWebhooksByTopic(topic)
+		assert.T(t, webhooks == nil, topic)
+		assert.Equal(t, 1, len(errs), topic)
+	}
+}
+
+// Should filter webhooks by topic and decode the response
+func TestGetWebhooksByTopic(t *testing.T) {
+	fixture := fixtureHandler(t, "/admin/webhooks.json", "webhooks_orders_create.json")
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "orders/create", r.URL.Query().Get("topic"))
+		fixture(w, r)
+	})
+	defer server.Close()
+
+	webhooks, errs := mock.GetWebhooksByTopic("orders/create")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(webhooks))
+	for _, webhook := range webhooks {
+		assert.Equal(t, "orders/create", webhook.Topic)
+	}
+	assert.Equal(t, int64(4759307), webhooks[1].ID)
+	assert.Equal(t, []string{"id", "email"}, webhooks[1].Fields)
+}
+
+// Should accept topics with dotted parts
+func TestGetWebhooksByTopicDotted(t *testing.T) {
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "customers.tags/update", r.URL.Query().Get("topic"))
+		w.Write([]byte(`{"webhooks": [{"id": 1, "topic": "customers.tags/update", "address": "https://myapp.example.com/hooks/tags"}]}`))
+	})
+	defer server.Close()
+
+	webhooks, errs := mock.GetWebhooksByTopic("customers.tags/update")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 1, len(webhooks))
+	assert.Equal(t, "customers.tags/update", webhooks[0].Topic)
+}
+
+// Should leave matching webhooks alone, update moved addresses and create the missing ones
+func TestEnsureWebhooks(t *testing.T) {
+	listing := loadFixture(t, "webhooks.json")
+	var calls []string
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == "GET" {
+			w.Write(listing)
+			return
+		}
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		webhook := body["webhook"]
+		if r.Method == "POST" {
+			assert.Equal(t, "carts/update", webhook["topic"])
+			assert.Equal(t, "json", webhook["format"])
+			webhook["id"] = 1234
+		}
+		json.NewEncoder(w).Encode(body)
+	})
+	defer server.Close()
+
+	webhooks, errs := mock.EnsureWebhooks([]Webhook{
+		{Topic: "orders/create", Address: "https://myapp.example.com/hooks/orders"},
+		{Topic: "app/uninstalled", Address: "https://myapp.example.com/hooks/uninstalled"},
+		{Topic: "carts/update", Address: "https://myapp.example.com/hooks/carts"},
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{
+		"GET /admin/webhooks.json",
+		"PUT /admin/webhooks/901431826.json",
+		"POST /admin/webhooks.json",
+	}, calls)
+	assert.Equal(t, 3, len(webhooks))
+	assert.Equal(t, int64(4759306), webhooks[0].ID)
+	assert.Equal(t, int64(901431826), webhooks[1].ID)
+	assert.Equal(t, "https://myapp.example.com/hooks/uninstalled", webhooks[1].Address)
+	assert.Equal(t, int64(1234), webhooks[2].ID)
+}
+
+// Should find a matching webhook on a later page instead of creating a duplicate
+func TestEnsureWebhooksPaged(t *testing.T) {
+	var calls []string
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method != "GET" {
+			t.Errorf("unexpected %v request", r.Method)
+			return
+		}
+		if r.URL.Query().Get("page_info") == "" {
+			assert.Equal(t, "250", r.URL.Query().Get("limit"))
+			w.Header().Set("Link", `<https://mock.myshopify.com/admin/webhooks.json?page_info=d2Vi&limit=250>; rel="next"`)
+			w.Write([]byte(`{"webhooks": [{"id": 1, "topic": "orders/create", "address": "https://myapp.example.com/hooks/orders"}]}`))
+			return
+		}
+		assert.Equal(t, "d2Vi", r.URL.Query().Get("page_info"))
+		w.Write([]byte(`{"webhooks": [{"id": 2, "topic": "carts/update", "address": "https://myapp.example.com/hooks/carts"}]}`))
+	})
+	defer server.Close()
+
+	webhooks, errs := mock.EnsureWebhooks([]Webhook{
+		{Topic: "orders/create", Address: "https://myapp.example.com/hooks/orders"},
+		{Topic: "carts/update", Address: "https://myapp.example.com/hooks/carts"},
+	})
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"GET /admin/webhooks.json", "GET /admin/webhooks.json"}, calls)
+	assert.Equal(t, int64(1), webhooks[0].ID)
+	assert.Equal(t, int64(2), webhooks[1].ID)
+}
+
+// Should delete only the webhooks pointing at the address, on every page
+func TestDeleteWebhooksByAddress(t *testing.T) {
+	listing := loadFixture(t, "webhooks.json")
+	var deleted []string
+	mock, server := newMockShopify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Query().Get("page_info") == "" {
+			assert.Equal(t, "https://myapp.example.com/hooks/orders", r.URL.Query().Get("address"))
+			w.Header().Set("Link", `<https://mock.myshopify.com/admin/webhooks.json?page_info=d2Vi&limit=250>; rel="next"`)
+			// shopify is expected to filter already, answer with everything to check the client does too
+			w.Write(listing)
+			return
+		}
+		if r.Method == "GET" {
+			assert.Equal(t, "d2Vi", r.URL.Query().Get("page_info"))
+			w.Write([]byte(`{"webhooks": [{"id": 4759307, "topic": "orders/updated", "address": "https://myapp.example.com/hooks/orders"}]}`))
+			return
+		}
+		assert.Equal(t, "DELETE", r.Method)
+		deleted = append(deleted, r.URL.Path)
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	errs := mock.DeleteWebhooksByAddress("https://myapp.example.com/hooks/orders")
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, []string{"/admin/webhooks/4759306.json", "/admin/webhooks/4759307.json"}, deleted)
+}
+
+// Should extract the shop domain from an app/uninstalled webhook
+func TestHandleAppUninstalled(t *testing.T) {
+	shopDomain, err := HandleAppUninstalled(loadFixture(t, "webhook_app_uninstalled.json"))
+
+	assert.T(t, err == nil, err)
+	assert.Equal(t, "apple.myshopify.com", shopDomain)
+}
+
+// Should reject payloads that are not a shop
+func TestHandleAppUninstalledInvalid(t *testing.T) {
+	for _, body := range []string{``, `[]`, `{"id": 690933842}`, `{"myshopify_domain": "apple.myshopify.com"}`, `{"id": 1, "myshopify_domain": "evil.example.com"}`} {
+		shopDomain, err := HandleAppUninstalled([]byte(body))
+		assert.Equal(t, "", shopDomain, body)
+		assert.T(t, err != nil, body)
+	}
+}
+
+// signedWebhookHeaders returns the headers shopify sends along a webhook signed with secret
+func signedWebhookHeaders(body []byte, secret string) http.Header {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	headers := http.Header{}
+	headers.Set("X-Shopify-Hmac-Sha256", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	headers.Set("X-Shopify-Topic", "orders/create")
+	headers.Set("X-Shopify-Shop-Domain", "apple.myshopify.com")
+	return headers
+}
+
+// Should verify a payload signed with the app secret
+func TestParseWebhook(t *testing.T) {
+	body := []byte(`{"id": 450789469, "email": "bob.norman@hostmail.com"}`)
+
+	topic, shopDomain, verified, err := ParseWebhook(signedWebhookHeaders(body, "hush"), body, "hush")
+
+	assert.T(t, err == nil, err)
+	assert.T(t, verified)
+	assert.Equal(t, "orders/create", topic)
+	assert.Equal(t, "apple.myshopify.com", shopDomain)
+}
+
+// Should not verify a payload signed with another secret or tampered with
+func TestParseWebhookWrongSecret(t *testing.T) {
+	body := []byte(`{"id": 450789469, "email": "bob.norman@hostmail.com"}`)
+	headers := signedWebhookHeaders(body, "not-the-secret")
+
+	topic, _, verified, err := ParseWebhook(headers, body, "hush")
+	assert.T(t, err == nil, err)
+	assert.T(t, !verified)
+	assert.Equal(t, "orders/create", topic)
+
+	_, _, verified, _ = ParseWebhook(signedWebhookHeaders(body, "hush"), []byte(`{"id": 1}`), "hush")
+	assert.T(t, !verified)
+}
+
+// Should fail on requests missing shopify's headers
+func TestParseWebhookMissingHeaders(t *testing.T) {
+	body := []byte(`{}`)
+	for _, header := range []string{"X-Shopify-Hmac-Sha256", "X-Shopify-Topic", "X-Shopify-Shop-Domain"} {
+		headers := signedWebhookHeaders(body, "hush")
+		headers.Del(header)
+		_, _, verified, err := ParseWebhook(headers, body, "hush")
+		assert.T(t, err != nil, header)
+		assert.T(t, !verified, header)
+	}
+}
+
+// Should decode the subscriptions along with their HTTP or Pub/Sub endpoint
+func TestGetWebhookSubscriptions(t *testing.T) {
+	mock, server := newMockShopify(t, fixtureHandler(t, "/admin/api/graphql.json", "graphql_webhook_subscriptions.json"))
+	defer server.Close()
+
+	subscriptions, errs := mock.GetWebhookSubscriptions()
+
+	assert.T(t, errs == nil, errs)
+	assert.Equal(t, 2, len(subscriptions))
+	assert.Equal(t, "ORDERS_CREATE", subscriptions[0].Topic)
+	assert.Equal(t, "JSON", subscriptions[0].Format)
+	assert.Equal(t, "WebhookHttpEndpoint", subscriptions[0].EndpointType)
+	assert.Equal(t, "https://example.com/webhooks/orders", subscriptions[0].Endpoint)
+	assert.Equal(t, []string{"id", "name", "line_items"}, subscriptions[0].IncludeFields)
+	assert.Equal(t, "WebhookPubSubEndpoint", subscriptions[1].EndpointType)
+	assert.Equal(t, "pubsub://my-project:shopify-products", subscriptions[1].Endpoint)
+	assert.Equal(t, []string{"custom"}, subscriptions[1].MetafieldNamespaces)
+}